@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/schedule"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backfillFrom    string
+	backfillTo      string
+	backfillEntryID string
+	backfillDryRun  bool
+)
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "create any missing scheduled entries over a date range",
+	Run:   backfillRun,
+}
+
+func init() {
+	backfillCmd.Flags().StringVar(&backfillFrom, "from", "", "first date to backfill (e.g. 2024-08-02, last monday, -2w)")
+	backfillCmd.Flags().StringVar(&backfillTo, "to", "", "last date to backfill (default: today)")
+	backfillCmd.Flags().StringVar(&backfillEntryID, "id", "", "only backfill the entry with this ID")
+	backfillCmd.Flags().BoolVar(&backfillDryRun, "dry-run", false, "list the files that would be created without creating them")
+	rootCmd.AddCommand(backfillCmd)
+}
+
+// backfillRun is the run function for the backfill command
+// It creates a file for every scheduled occurrence of an entry in the date range that does not already have one
+func backfillRun(_ *cobra.Command, _ []string) {
+	from, to, err := backfillRange()
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing date range")
+		os.Exit(1)
+	}
+	logger.Log.Debug().Str("from", from.Format(time.DateOnly)).
+		Str("to", to.Format(time.DateOnly)).
+		Str("entry_id", backfillEntryID).
+		Bool("dry_run", backfillDryRun).
+		Str("command", "backfill").
+		Msg("backfilling scheduled entries with the 'backfill' command")
+
+	if backfillEntryID != "" {
+		if _, err := app.Config.FetchEntryByID(backfillEntryID); err != nil {
+			logger.Log.Err(err).Msg("error fetching entry")
+			os.Exit(1)
+		}
+	}
+
+	created := []string{}
+	for _, entry := range app.Config.Entries {
+		if backfillEntryID != "" && entry.ID != backfillEntryID {
+			continue
+		}
+		filePaths, err := backfillEntry(entry, from, to)
+		if err != nil {
+			logger.Log.Err(err).Str("entry_id", entry.ID).Msg("error backfilling entry")
+			os.Exit(1)
+		}
+		created = append(created, filePaths...)
+	}
+
+	if backfillDryRun {
+		fmt.Printf("%d file(s) would be created\n", len(created))
+		return
+	}
+	if len(created) > 0 {
+		updateIndex(created...)
+	}
+	fmt.Printf("%d file(s) created\n", len(created))
+}
+
+// backfillRange returns the date range to backfill from the --from and --to flags
+func backfillRange() (time.Time, time.Time, error) {
+	if backfillFrom == "" {
+		return time.Time{}, time.Time{}, errors.New("a start date must be specified with --from")
+	}
+	from, err := parseDateFlag(backfillFrom)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := parseDateFlag(backfillTo)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+// backfillEntry creates the missing files for every scheduled occurrence of the entry in the date range
+// Each file is named and rendered using the date it was scheduled for, rather than the launch time
+// Returns the paths of the files created (or that would be created, for a dry run)
+func backfillEntry(entry config.Entry, from time.Time, to time.Time) ([]string, error) {
+	occurrences, err := schedule.Occurrences(entry.Schedule, from, to)
+	if err != nil {
+		return nil, err
+	}
+	created := []string{}
+	for _, date := range occurrences {
+		entryApp, err := app.DeriveEntry(entry.ID, date)
+		if err != nil {
+			return created, err
+		}
+		filePath, err := entryApp.GetFilePath()
+		if err != nil {
+			return created, err
+		}
+		if _, err := os.Stat(filePath); err == nil {
+			logger.Log.Debug().Str("file_path", filePath).Msg("scheduled entry already exists")
+			continue
+		}
+		if !backfillDryRun {
+			if _, _, _, err := createEntryFile(entryApp, filePath); err != nil {
+				return created, err
+			}
+			commitEntry(entryApp, filePath)
+		}
+		fmt.Printf("%s\t%s\t%s\n", entry.ID, date.Format(time.DateOnly), filePath)
+		created = append(created, filePath)
+	}
+	return created, nil
+}
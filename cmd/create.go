@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"time"
 
+	"github.com/matthewchivers/journal/pkg/application"
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/editor"
+	"github.com/matthewchivers/journal/pkg/encryption"
 	"github.com/matthewchivers/journal/pkg/fileops"
+	"github.com/matthewchivers/journal/pkg/hooks"
 	"github.com/matthewchivers/journal/pkg/logger"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
@@ -17,15 +24,23 @@ type cliParameters struct {
 	fileName      string
 	topic         string
 	editor        string
+	templateName  string
+	date          string
+	onExists      string
+	tags          []string
 }
 
 type cliFlags struct {
 	noOpen bool
+	wait   bool
 }
 
 var (
 	params cliParameters
 	flags  cliFlags
+
+	// hookRunner runs hooks, with their output on the terminal
+	hookRunner = hooks.Runner{Stdout: os.Stdout, Stderr: os.Stderr}
 )
 
 var createCmd = &cobra.Command{
@@ -42,8 +57,13 @@ func init() {
 	createCmd.PersistentFlags().StringVar(&params.fileExtension, "extension", "", "file extension to use")
 	createCmd.PersistentFlags().StringVar(&params.fileName, "filename", "", "file name to use")
 	createCmd.PersistentFlags().StringVar(&params.topic, "topic", "", "topic to use for templating")
+	createCmd.PersistentFlags().StringSliceVar(&params.tags, "tag", nil, "tag to give the entry (repeatable, or comma separated)")
+	createCmd.PersistentFlags().StringVar(&params.templateName, "template", "", "document template to populate the file with")
+	createCmd.PersistentFlags().StringVar(&params.date, "date", "", "date to create the entry for (e.g. 2024-08-02, yesterday, last friday, +3d)")
+	createCmd.PersistentFlags().StringVar(&params.onExists, "on-exists", "", "what to do if the file already exists (open, error, append-section, suffix, overwrite)")
 	createCmd.PersistentFlags().StringVar(&params.editor, "editor", "", "editor to use for editing the file")
 	createCmd.PersistentFlags().BoolVar(&flags.noOpen, "no-open", false, "do not open the file in the editor after creation")
+	createCmd.PersistentFlags().BoolVar(&flags.wait, "wait", false, "wait for the editor to close the file, then run post-edit steps")
 	rootCmd.AddCommand(createCmd)
 }
 
@@ -58,22 +78,123 @@ func createRun(_ *cobra.Command, _ []string) {
 	logger.Log.Info().Str("file_path", filePath).
 		Str("entry_id", app.EntryID).
 		Msg("creating new journal entry")
-	if err := fileops.CreateNewFile(filePath); err != nil {
+	filePath, content, created, err := createEntryFile(app, filePath)
+	if err != nil {
 		logger.Log.Err(err).Msg("error creating file")
 		os.Exit(1)
 	}
+	updateIndex(filePath)
+	commitEntry(app, filePath)
 	editor, err := app.GetEditor()
 	if err != nil {
 		logger.Log.Err(err).Msg("error getting editor")
 		os.Exit(1)
 	}
-	if !flags.noOpen {
-		if err := editor.OpenFile(filePath); err != nil {
-			logger.Log.Err(err).Msg("error opening file in editor")
-			os.Exit(1)
+	if flags.noOpen {
+		return
+	}
+	opened := time.Now()
+	// Post-edit steps can only run once the editor has closed the file, so are skipped for editors that return
+	// as soon as the file is opened
+	var postEdit func(string) error
+	if editor.Blocks() {
+		postEdit = func(editedPath string) error {
+			if err := app.PostEdit(editedPath, content, created, opened); err != nil {
+				return fmt.Errorf("post-edit steps failed: %w", err)
+			}
+			return nil
+		}
+	}
+	if err := openEntryFile(app, editor, filePath, postEdit); err != nil {
+		logger.Log.Err(err).Msg("error editing file")
+		os.Exit(1)
+	}
+	if !editor.Blocks() {
+		// The editor may still be open, so any edits are left for the next 'journal sync' to commit
+		return
+	}
+	runPostHooks(app, hooks.PostEdit, filePath, created)
+	updateIndex(filePath)
+	// Committed again once the editor has closed the file and the post-edit steps have run, with the edits
+	// (or the removal of an unchanged file)
+	commitEntry(app, filePath)
+}
+
+// createEntryFile renders the document template for the entry and creates the file at filePath,
+// applying the entry's onExists policy if the file already exists, and runs the pre- and post-create hooks
+// Returns the path of the file to open, the rendered content, and whether a new file was created
+func createEntryFile(entryApp *application.App, filePath string) (string, string, bool, error) {
+	if err := entryApp.RunHooks(hookRunner, hooks.PreCreate, filePath, false); err != nil {
+		return "", "", false, err
+	}
+	content, err := entryApp.GetDocumentContent()
+	if err != nil {
+		return "", "", false, err
+	}
+	filePath, created, err := writeEntryFile(entryApp, filePath, content)
+	if err != nil {
+		return "", "", false, err
+	}
+	runPostHooks(entryApp, hooks.PostCreate, filePath, created)
+	return filePath, content, created, nil
+}
+
+// runPostHooks runs the hooks for an event after the entry's file has been created or edited
+// Failing hooks are not fatal (the file has already been written), so errors are only logged
+// Hooks are not run if the file no longer exists (e.g. it was removed by the post-edit steps)
+func runPostHooks(entryApp *application.App, event hooks.Event, filePath string, created bool) {
+	if _, err := os.Stat(filePath); err != nil {
+		logger.Log.Debug().Str("event", string(event)).
+			Str("file_path", filePath).
+			Msg("file does not exist - not running hooks")
+		return
+	}
+	if err := entryApp.RunHooks(hookRunner, event, filePath, created); err != nil {
+		logger.Log.Warn().Err(err).Str("event", string(event)).Msg("error running hooks")
+	}
+}
+
+// writeEntryFile creates the file at filePath with the given content, applying the entry's onExists policy
+// if the file already exists
+// Returns the path of the file to open, and whether a new file was created
+func writeEntryFile(entryApp *application.App, filePath string, content string) (string, bool, error) {
+	opts := fileops.ExistsOptions{Policy: entryApp.OnExists}
+	var err error
+	if opts.Policy == config.OnExistsAppendSection {
+		if opts.Section, err = entryApp.GetSectionContent(); err != nil {
+			return "", false, err
+		}
+	}
+	if encryption.IsEncrypted(filePath) {
+		if opts.Key, err = entryApp.GetEncryptionKey(true); err != nil {
+			return "", false, err
 		}
 	}
+	return fileops.CreateFile(filePath, content, opts)
+}
 
+// openEntryFile opens an entry's file in the editor, then (if afterEdit is not nil) calls afterEdit with the path
+// of the file the editor opened, once the editor has returned
+// Encrypted files are decrypted to a private temporary file for the editor, which is re-encrypted over the entry's
+// file and securely deleted once the editor has returned (so the editor must wait for the file to be closed)
+func openEntryFile(entryApp *application.App, ed editor.Editor, filePath string, afterEdit func(string) error) error {
+	edit := func(editPath string) error {
+		if err := ed.OpenFile(editPath); err != nil {
+			return err
+		}
+		if afterEdit == nil {
+			return nil
+		}
+		return afterEdit(editPath)
+	}
+	if !encryption.IsEncrypted(filePath) {
+		return edit(filePath)
+	}
+	key, err := entryApp.GetEncryptionKey(false)
+	if err != nil {
+		return err
+	}
+	return encryption.Edit(key, filePath, edit)
 }
 
 // createPreRun is the pre-run function for the create command
@@ -86,13 +207,35 @@ func createPreRun(_ *cobra.Command, _ []string) {
 			Str("file_extension", params.fileExtension).
 			Str("file_name", params.fileName).
 			Str("topic", params.topic).
+			Strs("tags", params.tags).
+			Str("template", params.templateName).
+			Str("date", params.date).
+			Str("on_exists", params.onExists).
 			Str("editor", params.editor),
 	).Dict("flags",
-		zerolog.Dict().Bool("no_open", flags.noOpen),
+		zerolog.Dict().Bool("no_open", flags.noOpen).
+			Bool("wait", flags.wait),
 	).
 		Str("command", "create").
 		Msg("creating new journal entry with the 'create' command")
 
+	// The entry (and so its timezone) must be known before the date of the entry can be resolved
+	if err := app.SetEntryID(params.entryID); err != nil {
+		logger.Log.Err(err).Msg("error setting entry ID")
+		os.Exit(1)
+	}
+	if err := app.SetTimezone(""); err != nil {
+		logger.Log.Err(err).Msg("error setting timezone")
+		os.Exit(1)
+	}
+
+	entryTime, err := parseDateFlag(params.date)
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing date")
+		os.Exit(1)
+	}
+	app.SetLaunchTime(entryTime)
+
 	if err := app.PreparePatternData(); err != nil {
 		logger.Log.Err(err).Msg("error preparing pattern data")
 		os.Exit(1)
@@ -106,10 +249,10 @@ func createPreRun(_ *cobra.Command, _ []string) {
 
 // initialiseAppValues sets the values for the template dependencies
 func initialiseAppValues() error {
-	if err := app.SetEntryID(params.entryID); err != nil {
+	if err := app.SetTopic(params.topic); err != nil {
 		return err
 	}
-	if err := app.SetTopic(params.topic); err != nil {
+	if err := app.SetTags(params.tags); err != nil {
 		return err
 	}
 	if err := app.SetFileExtension(params.fileExtension); err != nil {
@@ -118,8 +261,23 @@ func initialiseAppValues() error {
 	if err := app.SetBaseDirectory(params.baseDirectory); err != nil {
 		return err
 	}
+	if err := app.SetTemplatesDirectory(""); err != nil {
+		return err
+	}
+	if err := app.SetTemplateName(params.templateName); err != nil {
+		return err
+	}
+	if err := app.SetOnExists(params.onExists); err != nil {
+		return err
+	}
 
-	// FileName and EntryDirectory depend on other values being set - call them last
+	// Carry-over tasks are found in the base directory, and only if the document template uses them
+	setCarryOver(app)
+	return initialisePathValues()
+}
+
+// initialisePathValues sets the file name, directory and editor, which depend on the other values being set
+func initialisePathValues() error {
 	if err := app.SetFileName(params.fileName); err != nil {
 		return err
 	}
@@ -127,9 +285,64 @@ func initialiseAppValues() error {
 		return err
 	}
 
-	// Editor relies on all paths being set
-	if err := app.SetEditor(params.editor); err != nil {
+	// Editor relies on all paths (and the wait setting) being set
+	if err := app.SetEditorWait(flags.wait); err != nil {
 		return err
 	}
-	return nil
+	return app.SetEditor(params.editor)
+}
+
+// setCarryOver sets the unfinished tasks carried over from the previous entry (found using the index), if the
+// entry's document template or front matter uses them
+// Failing to find them is not fatal: the entry is created without them
+func setCarryOver(entryApp *application.App) {
+	if !entryApp.UsesCarryOver() {
+		return
+	}
+	ix, err := loadIndex()
+	if err != nil {
+		logger.Log.Warn().Err(err).Msg("unable to carry over unfinished tasks")
+		return
+	}
+	if err := entryApp.SetCarryOver(ix.Records()); err != nil {
+		logger.Log.Warn().Err(err).Msg("unable to carry over unfinished tasks")
+	}
+}
+
+// createDerivedEntry creates a new entry of the given type, time, topic and tags (as 'journal create --no-open'
+// does), adds it to the index, and commits it (if enabled)
+// Returns the path of the entry's file
+func createDerivedEntry(entryID string, entryTime time.Time, topic string, tags []string) (string, error) {
+	entryApp, err := app.DeriveEntry(entryID, entryTime)
+	if err != nil {
+		return "", err
+	}
+	// The file name and directory are recalculated, as they may depend on the topic and tags
+	if err := entryApp.SetTopic(topic); err != nil {
+		return "", err
+	}
+	if err := entryApp.SetTags(tags); err != nil {
+		return "", err
+	}
+	setCarryOver(entryApp)
+	if err := entryApp.SetFileName(""); err != nil {
+		return "", err
+	}
+	if err := entryApp.SetEntryDirectory(""); err != nil {
+		return "", err
+	}
+	filePath, err := entryApp.GetFilePath()
+	if err != nil {
+		return "", err
+	}
+	logger.Log.Info().Str("file_path", filePath).
+		Str("entry_id", entryApp.EntryID).
+		Msg("creating new journal entry")
+	filePath, _, _, err = createEntryFile(entryApp, filePath)
+	if err != nil {
+		return "", err
+	}
+	updateIndex(filePath)
+	commitEntry(entryApp, filePath)
+	return filePath, nil
 }
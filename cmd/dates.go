@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/dateparse"
+)
+
+// parseDateFlag parses the value of a date flag: an ISO date (YYYY-MM-DD) or a relative
+// expression such as "yesterday", "last friday" or "+3d" (resolved relative to the launch time)
+// An empty value resolves to the application launch time
+func parseDateFlag(value string) (time.Time, error) {
+	if value == "" {
+		return app.LaunchTime, nil
+	}
+	return dateparse.Parse(value, app.LaunchTime)
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/matthewchivers/journal/pkg/application"
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/digest"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	digestPeriod   string
+	digestDate     string
+	digestEntryID  string
+	digestTags     []string
+	digestOnExists string
+	digestEditor   string
+	digestNoOpen   bool
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "combine the entries of a week, month or year into a digest",
+	Long: `Combine the entries of a week (Monday to Sunday), month or year into a single digest document,
+with a contents section and a heading for each entry, in date order.
+
+The digest is rendered with the digest template (digest.templateName, or a built-in template), and saved
+as an entry of the digest entry type (digest.entryId, default "digest"), whose patterns are populated with
+the first day of the period. An existing digest for the period is overwritten (keeping a .bak copy),
+unless the digest entry's onExists policy says otherwise.`,
+	Run: digestRun,
+}
+
+func init() {
+	digestCmd.Flags().StringVar(&digestPeriod, "period", string(digest.PeriodWeek), "period to digest (week, month or year)")
+	digestCmd.Flags().StringVar(&digestDate, "date", "", "a date within the period to digest (e.g. 2024-08-02, last week, -1m)")
+	digestCmd.Flags().StringVar(&digestEntryID, "id", "", "only include entries with this ID")
+	digestCmd.Flags().StringSliceVar(&digestTags, "tag", nil, "only include entries with this tag (repeatable, or comma separated)")
+	digestCmd.Flags().StringVar(&digestOnExists, "on-exists", "", "what to do if the digest already exists (open, error, append-section, suffix, overwrite)")
+	digestCmd.Flags().StringVar(&digestEditor, "editor", "", "editor to use for opening the digest")
+	digestCmd.Flags().BoolVar(&digestNoOpen, "no-open", false, "do not open the digest in the editor")
+	rootCmd.AddCommand(digestCmd)
+}
+
+// digestRun is the run function for the digest command
+// It writes a digest of the entries of the period, and opens it in the editor
+func digestRun(_ *cobra.Command, _ []string) {
+	logger.Log.Debug().Str("period", digestPeriod).
+		Str("date", digestDate).
+		Str("entry_id", digestEntryID).
+		Strs("tags", digestTags).
+		Str("on_exists", digestOnExists).
+		Str("command", "digest").
+		Msg("creating a digest with the 'digest' command")
+
+	digestApp, model, err := prepareDigest()
+	if err != nil {
+		logger.Log.Err(err).Msg("error preparing digest")
+		os.Exit(1)
+	}
+	filePath, err := writeDigest(digestApp, model)
+	if err != nil {
+		logger.Log.Err(err).Msg("error writing digest")
+		os.Exit(1)
+	}
+	fmt.Printf("digest of %d entries: %s\n", len(model.Entries), filePath)
+	if digestNoOpen {
+		return
+	}
+	if err := openDigest(digestApp, filePath); err != nil {
+		logger.Log.Err(err).Msg("error opening digest in editor")
+		os.Exit(1)
+	}
+}
+
+// prepareDigest derives the digest entry for the period, and collects the entries of the period
+func prepareDigest() (*application.App, *digest.Model, error) {
+	period, err := digest.ParsePeriod(digestPeriod)
+	if err != nil {
+		return nil, nil, err
+	}
+	date, err := parseDateFlag(digestDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	start, end := period.Bounds(date)
+
+	digestID := app.Config.Digest.EntryID
+	if digestID == "" {
+		digestID = config.DefaultDigestEntryID
+	}
+	if _, err := app.Config.FetchEntryByID(digestID); err != nil {
+		return nil, nil, fmt.Errorf("digests are saved as %q entries - configure an entry with this ID (or set digest.entryId): %w", digestID, err)
+	}
+	digestApp, err := app.DeriveEntry(digestID, start)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ix, err := loadIndex()
+	if err != nil {
+		return nil, nil, err
+	}
+	filter := catalog.Filter{EntryID: digestEntryID, Since: start, Until: end, Tags: digestTags}
+	records := []catalog.Record{}
+	for _, record := range filter.Apply(ix.Records()) {
+		if record.EntryID != digestID {
+			records = append(records, record)
+		}
+	}
+	model, err := digest.NewModel(*digestApp.TemplateData, period, start, records)
+	if err != nil {
+		return nil, nil, err
+	}
+	return digestApp, model, nil
+}
+
+// writeDigest renders the digest and writes it to the digest entry's file
+// Digests are regenerated (overwriting the existing digest) unless an onExists policy is set for the digest entry
+func writeDigest(digestApp *application.App, model *digest.Model) (string, error) {
+	policy := digestOnExists
+	if entry, err := digestApp.GetTargetEntry(); err == nil && policy == "" && entry.OnExists == "" {
+		policy = config.OnExistsOverwrite
+	}
+	if err := digestApp.SetOnExists(policy); err != nil {
+		return "", err
+	}
+	content, err := digestApp.GetDigestContent(model)
+	if err != nil {
+		return "", err
+	}
+	filePath, err := digestApp.GetFilePath()
+	if err != nil {
+		return "", err
+	}
+	filePath, _, err = writeEntryFile(digestApp, filePath, content)
+	if err != nil {
+		return "", err
+	}
+	updateIndex(filePath)
+	return filePath, nil
+}
+
+// openDigest opens the digest in the editor
+func openDigest(digestApp *application.App, filePath string) error {
+	if err := digestApp.SetEditorWait(false); err != nil {
+		return err
+	}
+	if err := digestApp.SetEditor(digestEditor); err != nil {
+		return err
+	}
+	editor, err := digestApp.GetEditor()
+	if err != nil {
+		return err
+	}
+	return openEntryFile(digestApp, editor, filePath, nil)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/schedule"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dueDate    string
+	dueEntryID string
+)
+
+var dueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "list the scheduled entries due today (or on a given date)",
+	Run:   dueRun,
+}
+
+func init() {
+	dueCmd.Flags().StringVar(&dueDate, "date", "", "date to check (e.g. 2024-08-02, yesterday, last friday; default: today)")
+	dueCmd.Flags().StringVar(&dueEntryID, "id", "", "only check the entry with this ID")
+	rootCmd.AddCommand(dueCmd)
+}
+
+// dueRun is the run function for the due command
+// It lists the entries scheduled for the date, and whether their files already exist
+func dueRun(_ *cobra.Command, _ []string) {
+	date, err := parseDateFlag(dueDate)
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing date")
+		os.Exit(1)
+	}
+	logger.Log.Debug().Str("date", date.Format(time.DateOnly)).
+		Str("entry_id", dueEntryID).
+		Str("command", "due").
+		Msg("checking scheduled entries with the 'due' command")
+
+	entries, err := dueEntries(date)
+	if err != nil {
+		logger.Log.Err(err).Msg("error evaluating schedules")
+		os.Exit(1)
+	}
+	if len(entries) == 0 {
+		fmt.Printf("no entries due on %s\n", date.Format(time.DateOnly))
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ENTRY\tDATE\tSTATUS\tPATH")
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", entry.entryID, date.Format(time.DateOnly), fileStatus(entry.filePath), entry.filePath)
+	}
+	if err := writer.Flush(); err != nil {
+		logger.Log.Err(err).Msg("error writing output")
+		os.Exit(1)
+	}
+}
+
+// dueEntry is an entry scheduled for a date, along with the path of its file
+type dueEntry struct {
+	entryID  string
+	filePath string
+}
+
+// dueEntries returns the configured entries scheduled for the given date
+// If an entry ID was specified, only that entry is considered
+func dueEntries(date time.Time) ([]dueEntry, error) {
+	entries := []dueEntry{}
+	for _, entry := range app.Config.Entries {
+		if dueEntryID != "" && entry.ID != dueEntryID {
+			continue
+		}
+		due, err := schedule.IsDue(entry.Schedule, date)
+		if err != nil {
+			return nil, fmt.Errorf("entry %s: %w", entry.ID, err)
+		}
+		if !due {
+			continue
+		}
+		filePath, err := scheduledFilePath(entry.ID, date)
+		if err != nil {
+			return nil, fmt.Errorf("entry %s: %w", entry.ID, err)
+		}
+		entries = append(entries, dueEntry{entryID: entry.ID, filePath: filePath})
+	}
+	return entries, nil
+}
+
+// scheduledFilePath returns the path of the file for the entry on the given date
+func scheduledFilePath(entryID string, date time.Time) (string, error) {
+	entryApp, err := app.DeriveEntry(entryID, date)
+	if err != nil {
+		return "", err
+	}
+	return entryApp.GetFilePath()
+}
+
+// fileStatus describes whether the file at the given path exists
+func fileStatus(filePath string) string {
+	if _, err := os.Stat(filePath); err == nil {
+		return "exists"
+	}
+	return "missing"
+}
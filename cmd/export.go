@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/matthewchivers/journal/pkg/export"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOutDir  string
+	exportTitle   string
+	exportEntryID string
+	exportSince   string
+	exportUntil   string
+	exportTags    []string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export the journal to another format",
+}
+
+var exportHTMLCmd = &cobra.Command{
+	Use:   "html",
+	Short: "export the journal as a static HTML site",
+	Long: `Export the journal as a static HTML site: a page for every entry (rendered from Markdown), with
+index pages by year (with calendars), month, week, entry type and tag, and links to the previous and next
+entries.
+
+Links are relative, so open index.html in a browser, or copy the directory to share a read-only snapshot.
+Existing files in the output directory are overwritten.`,
+	Run: exportHTMLRun,
+}
+
+func init() {
+	exportHTMLCmd.Flags().StringVar(&exportOutDir, "out", "", "directory to write the site to (required)")
+	exportHTMLCmd.Flags().StringVar(&exportTitle, "title", "Journal", "title of the site")
+	exportHTMLCmd.Flags().StringVar(&exportEntryID, "id", "", "only export entries with this ID")
+	exportHTMLCmd.Flags().StringVar(&exportSince, "since", "", "only export entries dated on or after this date (e.g. 2024-08-02, last monday, -2w)")
+	exportHTMLCmd.Flags().StringVar(&exportUntil, "until", "", "only export entries dated on or before this date")
+	exportHTMLCmd.Flags().StringSliceVar(&exportTags, "tag", nil, "only export entries with this tag (repeatable, or comma separated)")
+	exportCmd.AddCommand(exportHTMLCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+// exportHTMLRun is the run function for the export html command
+// It writes the indexed entries to the output directory as a static HTML site
+func exportHTMLRun(_ *cobra.Command, _ []string) {
+	if exportOutDir == "" {
+		logger.Log.Error().Msg("an output directory must be given with --out")
+		os.Exit(1)
+	}
+	filter, err := newRecordFilter(exportEntryID, exportSince, exportUntil, "", exportTags)
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing filters")
+		os.Exit(1)
+	}
+	logger.Log.Debug().Str("out_dir", exportOutDir).
+		Str("entry_id", filter.EntryID).
+		Time("since", filter.Since).
+		Time("until", filter.Until).
+		Strs("tags", filter.Tags).
+		Str("command", "export html").
+		Msg("exporting the journal with the 'export html' command")
+
+	ix, err := loadIndex()
+	if err != nil {
+		logger.Log.Err(err).Msg("error loading index")
+		os.Exit(1)
+	}
+	pages, err := export.NewPages(filter.Apply(ix.Records()))
+	if err != nil {
+		logger.Log.Err(err).Msg("error rendering entries")
+		os.Exit(1)
+	}
+	site := export.NewSite(exportTitle, pages)
+	if err := site.WriteHTML(exportOutDir); err != nil {
+		logger.Log.Err(err).Msg("error writing site")
+		os.Exit(1)
+	}
+	fmt.Printf("exported %d entries to %s\n", len(site.Pages), exportOutDir)
+}
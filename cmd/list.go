@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listEntryID string
+	listSince   string
+	listUntil   string
+	listTopic   string
+	listTags    []string
+	listJSON    bool
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list existing journal entries",
+	Run:   listRun,
+}
+
+func init() {
+	listCmd.Flags().StringVar(&listEntryID, "id", "", "only list entries with this ID")
+	listCmd.Flags().StringVar(&listSince, "since", "", "only list entries dated on or after this date (e.g. 2024-08-02, last monday, -2w)")
+	listCmd.Flags().StringVar(&listUntil, "until", "", "only list entries dated on or before this date")
+	listCmd.Flags().StringVar(&listTopic, "topic", "", "only list entries whose topic contains this text")
+	listCmd.Flags().StringSliceVar(&listTags, "tag", nil, "only list entries with this tag (repeatable, or comma separated)")
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "output the entries as JSON")
+	rootCmd.AddCommand(listCmd)
+}
+
+// listRun is the run function for the list command
+// It lists the existing entries (recognised by their directory and file name patterns)
+func listRun(_ *cobra.Command, _ []string) {
+	filter, err := newRecordFilter(listEntryID, listSince, listUntil, listTopic, listTags)
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing filters")
+		os.Exit(1)
+	}
+	logger.Log.Debug().Str("entry_id", filter.EntryID).
+		Time("since", filter.Since).
+		Time("until", filter.Until).
+		Str("topic", filter.Topic).
+		Strs("tags", filter.Tags).
+		Str("command", "list").
+		Msg("listing journal entries with the 'list' command")
+
+	ix, err := loadIndex()
+	if err != nil {
+		logger.Log.Err(err).Msg("error loading index")
+		os.Exit(1)
+	}
+	records := ix.Records()
+	records = filter.Apply(records)
+
+	if listJSON {
+		err = writeRecordsJSON(records)
+	} else {
+		err = writeRecordsTable(records)
+	}
+	if err != nil {
+		logger.Log.Err(err).Msg("error writing output")
+		os.Exit(1)
+	}
+}
+
+// newRecordFilter creates a filter from the values of the entry ID, date range, topic and tag flags
+func newRecordFilter(entryID, since, until, topic string, tags []string) (catalog.Filter, error) {
+	filter := catalog.Filter{
+		EntryID: entryID,
+		Topic:   topic,
+		Tags:    tags,
+	}
+	if since != "" {
+		since, err := parseDateFlag(since)
+		if err != nil {
+			return filter, err
+		}
+		filter.Since = since
+	}
+	if until != "" {
+		until, err := parseDateFlag(until)
+		if err != nil {
+			return filter, err
+		}
+		filter.Until = until
+	}
+	return filter, nil
+}
+
+// writeRecordsTable writes the records to stdout as a table
+func writeRecordsTable(records []catalog.Record) error {
+	if len(records) == 0 {
+		fmt.Println("no entries found")
+		return nil
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "DATE\tENTRY\tTOPIC\tTAGS\tPATH")
+	for _, record := range records {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", formatRecordDate(record.Date), record.EntryID, record.Topic,
+			strings.Join(record.Tags, ","), record.Path)
+	}
+	return writer.Flush()
+}
+
+// writeRecordsJSON writes the records to stdout as a JSON array
+func writeRecordsJSON(records []catalog.Record) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// formatRecordDate formats the date of a record for display ("-" if the date is unknown)
+func formatRecordDate(date time.Time) string {
+	if date.IsZero() {
+		return "-"
+	}
+	return date.Format(time.DateOnly)
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/paths"
+	"github.com/matthewchivers/journal/pkg/plugins"
+	"github.com/spf13/cobra"
+)
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "list the plugins that add commands to journal",
+	Long: `List the plugins that add commands to journal.
+
+A plugin is an executable named journal-<name>, in ~/.journal/plugins or on the PATH: 'journal <name>' runs
+it (if <name> is not a built-in command), with the remaining arguments. Plugins are given the configuration
+in the JOURNAL_CONFIG_PATH, JOURNAL_BASE_DIRECTORY and JOURNAL_CONFIG (JSON) environment variables.`,
+	Run: pluginsRun,
+}
+
+func init() {
+	rootCmd.AddCommand(pluginsCmd)
+}
+
+// pluginsRun is the run function for the plugins command
+// It lists the plugins found in the plugins directory and on the PATH
+func pluginsRun(_ *cobra.Command, _ []string) {
+	pluginsDir, err := pluginsDirectory()
+	if err != nil {
+		logger.Log.Err(err).Msg("error finding plugins directory")
+		os.Exit(1)
+	}
+	found := plugins.List(pluginsDir)
+	if len(found) == 0 {
+		fmt.Printf("no plugins found (add journal-<name> executables to %s or the PATH)\n", pluginsDir)
+		return
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "COMMAND\tPATH")
+	for _, plugin := range found {
+		if _, _, err := rootCmd.Find([]string{plugin.Name}); err == nil {
+			// Built-in commands take precedence
+			fmt.Fprintf(writer, "%s\t%s (hidden by the built-in command)\n", plugin.Name, plugin.Path)
+			continue
+		}
+		fmt.Fprintf(writer, "%s\t%s\n", plugin.Name, plugin.Path)
+	}
+	if err := writer.Flush(); err != nil {
+		logger.Log.Err(err).Msg("error writing output")
+		os.Exit(1)
+	}
+}
+
+// runPlugin runs the plugin named by the command line arguments, if they do not name a built-in command
+// The plugin is run with the arguments following its name, once the configuration has been loaded (using any
+// global flags preceding its name), and journal exits with the plugin's exit status
+// Returns false if the arguments do not name a plugin (so they are handled as usual)
+func runPlugin(args []string) bool {
+	if _, _, err := rootCmd.Find(args); err == nil {
+		return false
+	}
+	flagArgs, name, pluginArgs, ok := splitPluginArgs(args)
+	if !ok {
+		return false
+	}
+	pluginsDir, err := pluginsDirectory()
+	if err != nil {
+		return false
+	}
+	plugin, err := plugins.Find(name, pluginsDir)
+	if err != nil {
+		return false
+	}
+	if err := rootCmd.PersistentFlags().Parse(flagArgs); err != nil {
+		return false
+	}
+	rootCmd.PersistentPreRun(rootCmd, pluginArgs)
+
+	env, err := pluginEnviron()
+	if err != nil {
+		logger.Log.Err(err).Msg("error preparing plugin environment")
+		os.Exit(1)
+	}
+	logger.Log.Info().Str("plugin", plugin.Name).
+		Str("path", plugin.Path).
+		Strs("args", pluginArgs).
+		Msg("running plugin")
+	// #nosec G204: Subprocess launched with a potential tainted input or cmd arguments
+	// The plugin is an executable the user installed, named on the command line, and no shell is involved
+	cmd := exec.Command(plugin.Path, pluginArgs...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		logger.Log.Err(err).Str("plugin", plugin.Name).Msg("error running plugin")
+		os.Exit(1)
+	}
+	return true
+}
+
+// splitPluginArgs splits the command line arguments into the global flags before the plugin name, the name, and
+// the arguments after it
+// Returns false if there is no plugin name, or an unknown flag precedes it
+func splitPluginArgs(args []string) ([]string, string, []string, bool) {
+	flagSet := rootCmd.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case !strings.HasPrefix(arg, "-"):
+			return args[:i], arg, args[i+1:], true
+		case arg == "--":
+			return nil, "", nil, false
+		case strings.Contains(arg, "="):
+			continue
+		}
+		flagName := strings.TrimLeft(arg, "-")
+		flag := flagSet.Lookup(flagName)
+		if flag == nil && len(flagName) == 1 {
+			flag = flagSet.ShorthandLookup(flagName)
+		}
+		if flag == nil {
+			return nil, "", nil, false
+		}
+		if flag.NoOptDefVal == "" {
+			// The flag's value is the next argument
+			i++
+		}
+	}
+	return nil, "", nil, false
+}
+
+// pluginsDirectory returns the directory plugins are installed in (~/.journal/plugins)
+func pluginsDirectory() (string, error) {
+	appHome, err := paths.GetAppHomePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appHome, "plugins"), nil
+}
+
+// pluginEnviron returns the environment variables describing the configuration to plugins
+func pluginEnviron() ([]string, error) {
+	configJSON, err := app.Config.JSON()
+	if err != nil {
+		return nil, err
+	}
+	baseDir, err := absPath(app.Config.Paths.BaseDirectory)
+	if err != nil {
+		return nil, err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		"JOURNAL_CONFIG_PATH=" + app.ConfigPath,
+		"JOURNAL_BASE_DIRECTORY=" + baseDir,
+		"JOURNAL_INDEX_PATH=" + app.IndexPath,
+		"JOURNAL_CONFIG=" + string(configJSON),
+		"JOURNAL_EXECUTABLE=" + executable,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/index"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "rebuild the index of journal entries",
+	Long: `Rebuild the index of journal entries by scanning the journal.
+
+The index is rebuilt automatically when the entry configuration changes, and files written by
+journal are updated in it as they are written. Rebuild it to pick up files added, edited or removed
+outside of journal, or if it is ever out of step with the journal.`,
+	Run: reindexRun,
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+}
+
+// reindexRun is the run function for the reindex command
+// It rebuilds the index from scratch
+func reindexRun(_ *cobra.Command, _ []string) {
+	logger.Log.Debug().Str("index_path", app.IndexPath).
+		Str("command", "reindex").
+		Msg("rebuilding the index with the 'reindex' command")
+
+	ix, err := rebuildIndex()
+	if err != nil {
+		logger.Log.Err(err).Msg("error rebuilding index")
+		os.Exit(1)
+	}
+	fmt.Printf("indexed %d entries in %s\n", len(ix.Entries), app.IndexPath)
+}
+
+// loadIndex loads the index, building (and saving) it if there is no index yet or it is stale
+// The journal is not walked otherwise: files written by journal are kept up to date with updateIndex
+func loadIndex() (*index.Index, error) {
+	ix, err := index.Load(app.IndexPath, app.Config, app.Location)
+	if err == nil {
+		return ix, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, index.ErrStale) {
+		return nil, err
+	}
+	logger.Log.Info().Str("index_path", app.IndexPath).
+		Str("reason", err.Error()).
+		Msg("building index")
+	return rebuildIndex()
+}
+
+// refreshIndex brings the index up to date with the journal (e.g. after pulling changes), saving it if it
+// changed
+func refreshIndex() (*index.Index, error) {
+	ix, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	changed, err := ix.Refresh(app.Config, app.Location)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return ix, nil
+	}
+	logger.Log.Info().Str("index_path", app.IndexPath).Msg("index refreshed")
+	if err := ix.Save(app.IndexPath); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// rebuildIndex builds the index by scanning the journal, and saves it
+func rebuildIndex() (*index.Index, error) {
+	ix, err := index.Build(app.Config, app.Location)
+	if err != nil {
+		return nil, err
+	}
+	if err := ix.Save(app.IndexPath); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// updateIndex (re)indexes the given files, removing any that no longer exist
+// Files that are not recognised by the entry patterns (e.g. created with --filename) are not indexed
+// Failing to update the index is not fatal (it can be rebuilt with 'journal reindex'), so errors are only logged
+func updateIndex(filePaths ...string) {
+	if err := tryUpdateIndex(filePaths); err != nil {
+		logger.Log.Warn().Err(err).Msg("error updating index - run 'journal reindex' to rebuild it")
+	}
+}
+
+// tryUpdateIndex (re)indexes the given files (see updateIndex)
+func tryUpdateIndex(filePaths []string) error {
+	ix, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	for _, filePath := range filePaths {
+		if _, err := os.Stat(filePath); errors.Is(err, os.ErrNotExist) {
+			ix.Remove(filePath)
+			continue
+		}
+		record, ok, err := catalog.MatchPath(app.Config, app.Location, filePath)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.Log.Debug().Str("file_path", filePath).Msg("file not recognised by any entry - not indexed")
+			continue
+		}
+		if err := ix.Update(record); err != nil {
+			return err
+		}
+	}
+	return ix.Save(app.IndexPath)
+}
@@ -14,6 +14,7 @@ import (
 var (
 	cfgPath       string
 	loggingPath   string
+	timezone      string
 	logLevelInfo  bool
 	logLevelDebug bool
 	logJSON       bool
@@ -36,7 +37,8 @@ var rootCmd = &cobra.Command{
 			Bool("debug", logLevelDebug).
 			Dict("parameters", zerolog.Dict().
 				Str("config_path", cfgPath).
-				Str("log_path", loggingPath)).
+				Str("log_path", loggingPath).
+				Str("timezone", timezone)).
 			Msg("starting journal cli")
 
 		app, err = application.NewApp()
@@ -51,6 +53,19 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		logger.Log.Info().Msg("configuration loaded")
+
+		if err := app.SetTimezone(timezone); err != nil {
+			logger.Log.Err(err).Msg("error setting timezone")
+			os.Exit(1)
+		}
+		if err := app.SetIndexPath(""); err != nil {
+			logger.Log.Err(err).Msg("error setting index path")
+			os.Exit(1)
+		}
+		if err := app.SetKeyPath(""); err != nil {
+			logger.Log.Err(err).Msg("error setting key path")
+			os.Exit(1)
+		}
 	},
 	Run: func(_ *cobra.Command, _ []string) {
 		fmt.Println("welcome to journal cli: use 'journal --help' to see available commands")
@@ -58,7 +73,11 @@ var rootCmd = &cobra.Command{
 }
 
 // Execute runs the root command
+// Arguments naming a plugin rather than a built-in command run the plugin (see runPlugin)
 func Execute() error {
+	if runPlugin(os.Args[1:]) {
+		return nil
+	}
 	if err := rootCmd.Execute(); err != nil {
 		return err
 	}
@@ -68,6 +87,7 @@ func Execute() error {
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgPath, "config", "", "path to config file")
 	rootCmd.PersistentFlags().StringVar(&loggingPath, "logpath", "", "path to log file")
+	rootCmd.PersistentFlags().StringVar(&timezone, "tz", "", "IANA timezone to date entries in (e.g. Europe/London)")
 	rootCmd.PersistentFlags().BoolVar(&logLevelInfo, "info", false, "set log level to info")
 	rootCmd.PersistentFlags().BoolVar(&logLevelDebug, "debug", false, "set log level to debug")
 	rootCmd.PersistentFlags().BoolVar(&logJSON, "logjson", false, "set log output to JSON format")
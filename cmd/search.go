@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/frontmatter"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/search"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// highlightStart and highlightEnd are the terminal codes wrapped around matches (bold red)
+	highlightStart = "\x1b[1;31m"
+	highlightEnd   = "\x1b[0m"
+)
+
+var (
+	searchEntryID string
+	searchSince   string
+	searchUntil   string
+	searchTags    []string
+	searchRegex   bool
+	searchContext int
+	searchJSON    bool
+	searchNoColor bool
+)
+
+// searchResult is an entry file matching a search query, with its hits
+type searchResult struct {
+	catalog.Record
+	Hits []search.Hit `json:"hits"`
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "search the text of journal entries",
+	Long: `Search the text of journal entries.
+
+Queries are made of words and "quoted phrases" (matched case insensitively), combined with
+AND (implied between terms), OR and NOT (or a leading -), and grouped with parentheses, e.g.
+
+  journal search '"release notes" (api OR cli) -draft'
+
+With --regex, the query is a single (case sensitive) regular expression.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  searchRun,
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchEntryID, "id", "", "only search entries with this ID")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "only search entries dated on or after this date (e.g. 2024-08-02, last monday, -2w)")
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "only search entries dated on or before this date")
+	searchCmd.Flags().StringSliceVar(&searchTags, "tag", nil, "only search entries with this tag (repeatable, or comma separated)")
+	searchCmd.Flags().BoolVar(&searchRegex, "regex", false, "treat the query as a regular expression")
+	searchCmd.Flags().IntVarP(&searchContext, "context", "C", 0, "number of lines of context to show around each hit")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "output the results as JSON")
+	searchCmd.Flags().BoolVar(&searchNoColor, "no-color", false, "do not highlight matches")
+	rootCmd.AddCommand(searchCmd)
+}
+
+// searchRun is the run function for the search command
+// It searches the text of the existing entries (recognised by their directory and file name patterns)
+func searchRun(_ *cobra.Command, args []string) {
+	queryText := strings.Join(args, " ")
+	filter, err := newRecordFilter(searchEntryID, searchSince, searchUntil, "", searchTags)
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing filters")
+		os.Exit(1)
+	}
+	logger.Log.Debug().Str("query", queryText).
+		Bool("regex", searchRegex).
+		Str("entry_id", filter.EntryID).
+		Time("since", filter.Since).
+		Time("until", filter.Until).
+		Strs("tags", filter.Tags).
+		Str("command", "search").
+		Msg("searching journal entries with the 'search' command")
+
+	query, err := parseQuery(queryText)
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing search query")
+		os.Exit(1)
+	}
+	ix, err := loadIndex()
+	if err != nil {
+		logger.Log.Err(err).Msg("error loading index")
+		os.Exit(1)
+	}
+	records := ix.Records()
+	results := searchRecords(query, filter.Apply(records))
+
+	if searchJSON {
+		err = writeSearchJSON(results)
+	} else {
+		writeSearchResults(results, useColour())
+	}
+	if err != nil {
+		logger.Log.Err(err).Msg("error writing output")
+		os.Exit(1)
+	}
+}
+
+// parseQuery parses the search query according to the --regex flag
+func parseQuery(queryText string) (*search.Query, error) {
+	if searchRegex {
+		return search.ParseRegex(queryText)
+	}
+	return search.Parse(queryText)
+}
+
+// searchRecords searches the entries of the records (without their front matter), returning those that match
+// the query
+// Files that cannot be read are logged and skipped, and encrypted files are not searched
+func searchRecords(query *search.Query, records []catalog.Record) []searchResult {
+	results := []searchResult{}
+	for _, record := range records {
+		if record.Encrypted {
+			continue
+		}
+		content, ok, err := catalog.ReadRecord(record)
+		if err != nil {
+			logger.Log.Warn().Err(err).Str("file_path", record.Path).Msg("error reading file")
+			continue
+		}
+		if !ok {
+			continue
+		}
+		body := frontmatter.Body(content)
+		hits, ok := query.Search(body, searchContext)
+		if !ok {
+			continue
+		}
+		// Hits are numbered by their lines in the file, after the front matter
+		frontMatterLines := strings.Count(content[:len(content)-len(body)], "\n")
+		for i := range hits {
+			hits[i].Line += frontMatterLines
+		}
+		results = append(results, searchResult{Record: record, Hits: hits})
+	}
+	return results
+}
+
+// writeSearchResults writes the results to stdout, grouped by file
+func writeSearchResults(results []searchResult, colour bool) {
+	if len(results) == 0 {
+		fmt.Println("no matches found")
+		return
+	}
+	hitCount := 0
+	for i, result := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s (%s, %s)\n", result.Path, result.EntryID, formatRecordDate(result.Date))
+		writeHits(result.Hits, colour)
+		hitCount += len(result.Hits)
+	}
+	fmt.Printf("\n%d hit(s) in %d file(s)\n", hitCount, len(results))
+}
+
+// writeHits writes the hits of a file to stdout as "line: text", with context lines as "line- text"
+// Context shared by neighbouring hits is only written once
+func writeHits(hits []search.Hit, colour bool) {
+	printed := 0
+	for i, hit := range hits {
+		next := hit.Line + len(hit.After) + 1
+		if i+1 < len(hits) {
+			next = min(next, hits[i+1].Line)
+		}
+		start := hit.Line - len(hit.Before)
+		for j, line := range hit.Before {
+			if start+j > printed {
+				fmt.Printf("%6d- %s\n", start+j, line)
+			}
+		}
+		text := hit.Text
+		if colour {
+			text = hit.Highlight(highlightStart, highlightEnd)
+		}
+		fmt.Printf("%6d: %s\n", hit.Line, text)
+		for lineNum := hit.Line + 1; lineNum < next; lineNum++ {
+			fmt.Printf("%6d- %s\n", lineNum, hit.After[lineNum-hit.Line-1])
+		}
+		printed = next - 1
+	}
+}
+
+// writeSearchJSON writes the results to stdout as a JSON array
+func writeSearchJSON(results []searchResult) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// useColour reports whether matches should be highlighted: only when writing to a terminal,
+// and neither --no-color nor $NO_COLOR is set
+func useColour() bool {
+	if searchNoColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
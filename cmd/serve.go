@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/dateparse"
+	"github.com/matthewchivers/journal/pkg/export"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/search"
+	"github.com/spf13/cobra"
+)
+
+// readHeaderTimeout is the time allowed to read the headers of a request
+const readHeaderTimeout = 10 * time.Second
+
+var (
+	serveAddr  string
+	serveTitle string
+)
+
+// serveTemplates are the pages of the web UI that are not part of an exported site
+var serveTemplates = map[string]string{
+	"search": `{{define "content"}}<h1>Search</h1>
+<form action="{{link "search"}}" method="get"><input type="search" name="q" value="{{.Data.Query}}" size="40" autofocus> <button>Search</button></form>
+{{with .Data.Error}}<p class="error">{{.}}</p>{{end}}
+{{if .Data.Query}}<p class="meta">{{len .Data.Results}} matching entries</p>{{end}}
+{{range .Data.Results}}<h2><a href="{{link .Page.URL}}">{{.Page.Title}}</a></h2>
+<ul>
+{{range .Lines}}<li><code>{{.Line}}</code> {{.Text}}</li>
+{{end}}</ul>
+{{end}}{{end}}`,
+
+	"new": `{{define "content"}}<h1>New entry</h1>
+{{with .Data.Error}}<p class="error">{{.}}</p>{{end}}
+<form action="{{link "new"}}" method="post">
+<p><label>Entry type <select name="id">{{range .Data.EntryIDs}}<option{{if eq . $.Data.EntryID}} selected{{end}}>{{.}}</option>{{end}}</select></label></p>
+<p><label>Date <input name="date" value="{{.Data.Date}}" placeholder="today, yesterday, last friday, 2024-08-02"></label></p>
+<p><label>Topic <input name="topic" value="{{.Data.Topic}}"></label></p>
+<p><label>Tags <input name="tags" value="{{.Data.Tags}}" placeholder="work, client-x"></label></p>
+<p><button>Create</button></p>
+</form>{{end}}`,
+}
+
+// webServer serves the journal as a web UI
+type webServer struct {
+	// renderer renders the pages of the site
+	renderer *export.Renderer
+
+	// mu serialises requests, as they share the application state (and the index)
+	mu sync.Mutex
+}
+
+// searchPage is the data of the search page
+type searchPage struct {
+	Query   string
+	Error   string
+	Results []webSearchResult
+}
+
+// webSearchResult is an entry matching a search, with its matching lines
+type webSearchResult struct {
+	Page  *export.Page
+	Lines []webSearchLine
+}
+
+// webSearchLine is a matching line, with its matches highlighted
+type webSearchLine struct {
+	Line int
+	Text template.HTML
+}
+
+// newEntryPage is the data of the new entry form
+type newEntryPage struct {
+	EntryIDs []string
+	EntryID  string
+	Date     string
+	Topic    string
+	Tags     string
+	Error    string
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "browse, search and create journal entries in a web browser",
+	Long: `Start a web server for browsing, searching and creating journal entries in a web browser.
+
+The server only listens on the local machine (a loopback address such as 127.0.0.1 or localhost),
+and works entirely offline.`,
+	Run: serveRun,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "address to listen on (must be a loopback address)")
+	serveCmd.Flags().StringVar(&serveTitle, "title", "Journal", "title of the site")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// serveRun is the run function for the serve command
+// It serves the web UI until the process is stopped
+func serveRun(_ *cobra.Command, _ []string) {
+	logger.Log.Debug().Str("addr", serveAddr).
+		Str("command", "serve").
+		Msg("starting web server with the 'serve' command")
+
+	if err := checkLoopback(serveAddr); err != nil {
+		logger.Log.Err(err).Msg("invalid address")
+		os.Exit(1)
+	}
+	renderer, err := export.NewRenderer(serveTemplates)
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing templates")
+		os.Exit(1)
+	}
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		logger.Log.Err(err).Msg("error listening")
+		os.Exit(1)
+	}
+	ws := &webServer{renderer: renderer}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", ws.handleSearch)
+	mux.HandleFunc("/new", ws.handleNew)
+	mux.HandleFunc("/", ws.handlePage)
+	server := &http.Server{
+		Handler:           ws.guard(listener.Addr().String(), mux),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	fmt.Printf("serving journal at http://%s/ (press Ctrl+C to stop)\n", listener.Addr())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Log.Err(err).Msg("error serving")
+		os.Exit(1)
+	}
+}
+
+// checkLoopback checks that the address only listens on the local machine
+func checkLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("%q is not a loopback address (e.g. 127.0.0.1:8080 or localhost:8080)", addr)
+}
+
+// guard serialises requests, and rejects requests that are not addressed to the server by a loopback name (to
+// defeat DNS rebinding), and form submissions from other sites
+func (ws *webServer) guard(listenAddr string, next http.Handler) http.Handler {
+	_, port, _ := net.SplitHostPort(listenAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, hostPort, err := net.SplitHostPort(r.Host)
+		if err != nil || hostPort != port || checkLoopback(net.JoinHostPort(host, hostPort)) != nil {
+			http.Error(w, "invalid host", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); r.Method != http.MethodGet && origin != "" && origin != "http://"+r.Host {
+			http.Error(w, "cross-origin request refused", http.StatusForbidden)
+			return
+		}
+		ws.mu.Lock()
+		defer ws.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loadSite builds the site from the index
+func loadSite() (*export.Site, error) {
+	ix, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	pages, err := export.NewPages(ix.Records())
+	if err != nil {
+		return nil, err
+	}
+	site := export.NewSite(serveTitle, pages)
+	site.Live = true
+	return site, nil
+}
+
+// handlePage serves the pages of the site (as exported by 'journal export html')
+func (ws *webServer) handlePage(w http.ResponseWriter, r *http.Request) {
+	url := strings.TrimPrefix(r.URL.Path, "/")
+	if url == "" || strings.HasSuffix(url, "/") {
+		url += "index.html"
+	}
+	site, err := loadSite()
+	if err != nil {
+		serverError(w, err)
+		return
+	}
+	var page strings.Builder
+	found, err := ws.renderer.RenderPage(&page, site, url)
+	if err != nil {
+		serverError(w, err)
+		return
+	}
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	writeHTML(w, page.String())
+}
+
+// handleSearch serves the search page, searching the entries for the query (as 'journal search' does)
+func (ws *webServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	site, err := loadSite()
+	if err != nil {
+		serverError(w, err)
+		return
+	}
+	data := searchPage{Query: strings.TrimSpace(r.URL.Query().Get("q"))}
+	if data.Query != "" {
+		data.Results, err = searchSite(site, data.Query)
+		if err != nil {
+			data.Error = err.Error()
+		}
+	}
+	ws.render(w, "search", "search", export.PageData{Site: site, Title: "Search", Data: data})
+}
+
+// searchSite searches the entries of the site, returning the matching entries with their matching lines
+func searchSite(site *export.Site, queryText string) ([]webSearchResult, error) {
+	query, err := search.Parse(queryText)
+	if err != nil {
+		return nil, err
+	}
+	pagesByPath := map[string]*export.Page{}
+	for _, page := range site.Pages {
+		pagesByPath[page.Path] = page
+	}
+	results := []webSearchResult{}
+	for _, result := range searchRecords(query, site.Records()) {
+		webResult := webSearchResult{Page: pagesByPath[result.Path]}
+		for _, hit := range result.Hits {
+			webResult.Lines = append(webResult.Lines, webSearchLine{Line: hit.Line, Text: highlightHTML(hit)})
+		}
+		results = append(results, webResult)
+	}
+	return results, nil
+}
+
+// highlightHTML returns the escaped text of a hit, with its matches marked
+func highlightHTML(hit search.Hit) template.HTML {
+	var b strings.Builder
+	pos := 0
+	for _, m := range hit.Matches {
+		b.WriteString(template.HTMLEscapeString(hit.Text[pos:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(template.HTMLEscapeString(hit.Text[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		pos = m[1]
+	}
+	b.WriteString(template.HTMLEscapeString(hit.Text[pos:]))
+	// #nosec G203: The used method does not auto-escape HTML
+	// Every part of the text has been escaped
+	return template.HTML(b.String())
+}
+
+// handleNew serves the new entry form, and creates entries when it is submitted
+func (ws *webServer) handleNew(w http.ResponseWriter, r *http.Request) {
+	site, err := loadSite()
+	if err != nil {
+		serverError(w, err)
+		return
+	}
+	data := newEntryPage{EntryID: app.Config.DefaultEntry}
+	for _, entry := range app.Config.Entries {
+		data.EntryIDs = append(data.EntryIDs, entry.ID)
+	}
+	sort.Strings(data.EntryIDs)
+
+	if r.Method == http.MethodPost {
+		data.EntryID = r.PostFormValue("id")
+		data.Date = r.PostFormValue("date")
+		data.Topic = r.PostFormValue("topic")
+		data.Tags = r.PostFormValue("tags")
+		filePath, err := createFromForm(data)
+		if err == nil {
+			http.Redirect(w, r, "/"+entryURL(filePath), http.StatusSeeOther)
+			return
+		}
+		data.Error = err.Error()
+	}
+	ws.render(w, "new", "new", export.PageData{Site: site, Title: "New entry", Data: data})
+}
+
+// createFromForm creates a new entry from the values of the new entry form, as 'journal create --no-open' does
+// Returns the path of the entry's file
+func createFromForm(form newEntryPage) (string, error) {
+	now := time.Now()
+	if app.Location != nil {
+		now = now.In(app.Location)
+	}
+	entryTime := now
+	if strings.TrimSpace(form.Date) != "" {
+		var err error
+		if entryTime, err = dateparse.Parse(form.Date, now); err != nil {
+			return "", err
+		}
+	}
+	tags := strings.FieldsFunc(form.Tags, func(r rune) bool { return r == ',' })
+	return createDerivedEntry(form.EntryID, entryTime, strings.TrimSpace(form.Topic), tags)
+}
+
+// entryURL returns the path (relative to the root of the site) of the page of the entry with the given file,
+// or the home page if the file is not a recognised entry (e.g. it does not match its entry's patterns)
+func entryURL(filePath string) string {
+	site, err := loadSite()
+	if err != nil {
+		return ""
+	}
+	for _, page := range site.Pages {
+		if page.Path == filePath {
+			return page.URL
+		}
+	}
+	return ""
+}
+
+// render renders a page of the web UI with the named template
+func (ws *webServer) render(w http.ResponseWriter, name string, url string, data export.PageData) {
+	var page strings.Builder
+	if err := ws.renderer.Render(&page, name, url, data); err != nil {
+		serverError(w, err)
+		return
+	}
+	writeHTML(w, page.String())
+}
+
+// writeHTML writes an HTML page to the response
+func writeHTML(w http.ResponseWriter, page string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := fmt.Fprint(w, page); err != nil {
+		logger.Log.Warn().Err(err).Msg("error writing response")
+	}
+}
+
+// serverError logs an error and responds with an internal server error
+func serverError(w http.ResponseWriter, err error) {
+	logger.Log.Err(err).Msg("error serving request")
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
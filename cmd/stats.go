@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/index"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsEntryID string
+	statsSince   string
+	statsUntil   string
+)
+
+// entryStats are the statistics for the entries of one entry type
+type entryStats struct {
+	entryID string
+	entries int
+	words   int
+	first   time.Time
+	last    time.Time
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "show statistics about journal entries",
+	Run:   statsRun,
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsEntryID, "id", "", "only include entries with this ID")
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "only include entries dated on or after this date (e.g. 2024-08-02, last monday, -2w)")
+	statsCmd.Flags().StringVar(&statsUntil, "until", "", "only include entries dated on or before this date")
+	rootCmd.AddCommand(statsCmd)
+}
+
+// statsRun is the run function for the stats command
+// It shows the number of entries, words and the date range of each entry type (from the index)
+func statsRun(_ *cobra.Command, _ []string) {
+	filter, err := newRecordFilter(statsEntryID, statsSince, statsUntil, "", nil)
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing filters")
+		os.Exit(1)
+	}
+	logger.Log.Debug().Str("entry_id", filter.EntryID).
+		Time("since", filter.Since).
+		Time("until", filter.Until).
+		Str("command", "stats").
+		Msg("showing statistics with the 'stats' command")
+
+	ix, err := loadIndex()
+	if err != nil {
+		logger.Log.Err(err).Msg("error loading index")
+		os.Exit(1)
+	}
+	entries := []index.Entry{}
+	for _, entry := range ix.Entries {
+		if filter.Matches(entry.Record) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := writeStatsTable(collectStats(entries)); err != nil {
+		logger.Log.Err(err).Msg("error writing output")
+		os.Exit(1)
+	}
+}
+
+// collectStats totals the entries by entry type, in order of first appearance, followed by a total for all entries
+func collectStats(entries []index.Entry) []entryStats {
+	total := entryStats{entryID: "TOTAL"}
+	byID := map[string]*entryStats{}
+	order := []string{}
+	for _, entry := range entries {
+		stats, ok := byID[entry.EntryID]
+		if !ok {
+			stats = &entryStats{entryID: entry.EntryID}
+			byID[entry.EntryID] = stats
+			order = append(order, entry.EntryID)
+		}
+		stats.add(entry)
+		total.add(entry)
+	}
+	results := []entryStats{}
+	for _, entryID := range order {
+		results = append(results, *byID[entryID])
+	}
+	return append(results, total)
+}
+
+// add adds an entry to the statistics
+func (s *entryStats) add(entry index.Entry) {
+	s.entries++
+	s.words += entry.WordCount
+	if entry.Date.IsZero() {
+		return
+	}
+	if s.first.IsZero() || entry.Date.Before(s.first) {
+		s.first = entry.Date
+	}
+	if entry.Date.After(s.last) {
+		s.last = entry.Date
+	}
+}
+
+// writeStatsTable writes the statistics to stdout as a table
+func writeStatsTable(stats []entryStats) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ENTRY\tENTRIES\tWORDS\tFIRST\tLAST")
+	for _, s := range stats {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%s\t%s\n", s.entryID, s.entries, s.words, formatRecordDate(s.first), formatRecordDate(s.last))
+	}
+	return writer.Flush()
+}
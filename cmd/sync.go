@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/application"
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/gitops"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/paths"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncRemote  string
+	syncBranch  string
+	syncMessage string
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "synchronise the journal with a git remote",
+	Long: `Synchronise the journal with a git remote: commit any uncommitted changes within the base directory,
+pull the branch from the remote (rebasing local commits onto it), then push it to the remote.
+
+The base directory must be within a git repository. The remote and branch default to the git section of
+the configuration (or origin and the current branch).`,
+	Run: syncRun,
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncRemote, "remote", "", "remote to synchronise with: a remote name, URL or repository path")
+	syncCmd.Flags().StringVar(&syncBranch, "branch", "", "branch to synchronise")
+	syncCmd.Flags().StringVar(&syncMessage, "message", "Sync journal", "message for committing uncommitted changes")
+	rootCmd.AddCommand(syncCmd)
+}
+
+// syncRun is the run function for the sync command
+// It commits the changes within the base directory, then pulls from and pushes to the remote
+func syncRun(_ *cobra.Command, _ []string) {
+	remote := firstNonEmpty(syncRemote, app.Config.Git.Remote, config.DefaultGitRemote)
+	logger.Log.Debug().Str("remote", remote).
+		Str("branch", syncBranch).
+		Str("command", "sync").
+		Msg("synchronising the journal with the 'sync' command")
+
+	baseDir, err := absPath(app.Config.Paths.BaseDirectory)
+	if err != nil {
+		logger.Log.Err(err).Msg("error finding base directory")
+		os.Exit(1)
+	}
+	repo, err := gitops.Open(baseDir)
+	if err != nil {
+		logger.Log.Err(err).Msg("error opening git repository - run 'git init' in the base directory")
+		os.Exit(1)
+	}
+	branch := firstNonEmpty(syncBranch, app.Config.Git.Branch)
+	if branch == "" {
+		if branch, err = repo.CurrentBranch(); err != nil {
+			logger.Log.Err(err).Msg("error finding branch")
+			os.Exit(1)
+		}
+	}
+	committed, err := repo.Commit(syncMessage, baseDir)
+	if err != nil {
+		logger.Log.Err(err).Msg("error committing changes")
+		os.Exit(1)
+	}
+	if committed {
+		fmt.Println("committed uncommitted changes")
+	}
+	if err := repo.Sync(remote, branch); err != nil {
+		logger.Log.Err(err).Msg("error synchronising with remote")
+		os.Exit(1)
+	}
+	// Pulled changes may have added, edited or removed entries
+	if _, err := refreshIndex(); err != nil {
+		logger.Log.Warn().Err(err).Msg("error rebuilding index - run 'journal reindex' to rebuild it")
+	}
+	fmt.Printf("synchronised %s with %s (%s)\n", repo.Dir, remote, branch)
+}
+
+// commitEntry commits the changes to an entry's file to git, if enabled in the configuration
+// Failing to commit is not fatal (the changes are committed by the next commit or 'journal sync'), so errors are
+// only logged
+func commitEntry(entryApp *application.App, filePath string) {
+	if !app.Config.Git.Enabled {
+		return
+	}
+	if err := tryCommitEntry(entryApp, filePath); err != nil {
+		logger.Log.Warn().Err(err).Str("file_path", filePath).Msg("error committing entry to git")
+	}
+}
+
+// commitRecord commits the changes to an indexed entry's file to git, if enabled in the configuration
+// The commit message is rendered for the entry's type, date and topic
+func commitRecord(record catalog.Record) {
+	if !app.Config.Git.Enabled {
+		return
+	}
+	entryApp, err := recordApp(record)
+	if err != nil {
+		logger.Log.Warn().Err(err).Str("file_path", record.Path).Msg("error committing entry to git")
+		return
+	}
+	commitEntry(entryApp, record.Path)
+}
+
+// recordApp returns an App for an indexed entry: its entry type at its date (or the launch time, if it has none),
+// with its topic
+func recordApp(record catalog.Record) (*application.App, error) {
+	entryTime := record.Date
+	if entryTime.IsZero() {
+		entryTime = app.LaunchTime
+	}
+	entryApp, err := app.DeriveEntry(record.EntryID, entryTime)
+	if err != nil {
+		return nil, err
+	}
+	if err := entryApp.SetTopic(record.Topic); err != nil {
+		return nil, err
+	}
+	return entryApp, nil
+}
+
+// tryCommitEntry commits the changes to an entry's file to the git repository containing its base directory
+// Files outside of the base directory (e.g. created with --directory) are not committed
+func tryCommitEntry(entryApp *application.App, filePath string) error {
+	baseDir, err := absPath(entryApp.BaseDirectory)
+	if err != nil {
+		return err
+	}
+	filePath, err = filepath.Abs(filePath)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(baseDir, filePath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		logger.Log.Debug().Str("file_path", filePath).Msg("file is not within the base directory - not committed")
+		return nil
+	}
+	repo, err := gitops.Open(baseDir)
+	if err != nil {
+		return err
+	}
+	message, err := entryApp.GetCommitMessage()
+	if err != nil {
+		return err
+	}
+	_, err = repo.Commit(message, filePath)
+	return err
+}
+
+// absPath expands a leading "~" in the path and makes it absolute
+func absPath(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("path is empty")
+	}
+	expanded, err := paths.ExpandHome(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(expanded)
+}
+
+// firstNonEmpty returns the first of the values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/index"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tagsEntryID string
+	tagsSince   string
+	tagsUntil   string
+	tagsByName  bool
+)
+
+// tagUsage is the usage of a tag across the journal
+type tagUsage struct {
+	tag      string
+	count    int
+	lastUsed time.Time
+}
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "list the tags used in journal entries",
+	Long: `List the tags used in journal entries, with the number of entries using each tag and the date
+it was last used.
+
+Entries are tagged by the tags field of their front matter, #hashtags in their text, the tags of
+their entry configuration, or 'journal create --tag'.`,
+	Run: tagsRun,
+}
+
+func init() {
+	tagsCmd.Flags().StringVar(&tagsEntryID, "id", "", "only include entries with this ID")
+	tagsCmd.Flags().StringVar(&tagsSince, "since", "", "only include entries dated on or after this date (e.g. 2024-08-02, last monday, -2w)")
+	tagsCmd.Flags().StringVar(&tagsUntil, "until", "", "only include entries dated on or before this date")
+	tagsCmd.Flags().BoolVar(&tagsByName, "by-name", false, "sort tags by name (rather than by number of entries)")
+	rootCmd.AddCommand(tagsCmd)
+}
+
+// tagsRun is the run function for the tags command
+// It lists the tags of the indexed entries with their usage
+func tagsRun(_ *cobra.Command, _ []string) {
+	filter, err := newRecordFilter(tagsEntryID, tagsSince, tagsUntil, "", nil)
+	if err != nil {
+		logger.Log.Err(err).Msg("error parsing filters")
+		os.Exit(1)
+	}
+	logger.Log.Debug().Str("entry_id", filter.EntryID).
+		Time("since", filter.Since).
+		Time("until", filter.Until).
+		Str("command", "tags").
+		Msg("listing tags with the 'tags' command")
+
+	ix, err := loadIndex()
+	if err != nil {
+		logger.Log.Err(err).Msg("error loading index")
+		os.Exit(1)
+	}
+	entries := []index.Entry{}
+	for _, entry := range ix.Entries {
+		if filter.Matches(entry.Record) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := writeTagsTable(collectTagUsage(entries, tagsByName)); err != nil {
+		logger.Log.Err(err).Msg("error writing output")
+		os.Exit(1)
+	}
+}
+
+// collectTagUsage counts the entries using each tag, sorted by count (most used first) or by name
+// Entries without a date (from their path) count as used on the day they were last modified
+func collectTagUsage(entries []index.Entry, byName bool) []tagUsage {
+	usage := map[string]*tagUsage{}
+	for _, entry := range entries {
+		used := entry.Date
+		if used.IsZero() {
+			used = entry.ModTime
+		}
+		for _, tag := range entry.Tags {
+			u, ok := usage[tag]
+			if !ok {
+				u = &tagUsage{tag: tag}
+				usage[tag] = u
+			}
+			u.count++
+			if used.After(u.lastUsed) {
+				u.lastUsed = used
+			}
+		}
+	}
+	results := []tagUsage{}
+	for _, u := range usage {
+		results = append(results, *u)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !byName && results[i].count != results[j].count {
+			return results[i].count > results[j].count
+		}
+		return results[i].tag < results[j].tag
+	})
+	return results
+}
+
+// writeTagsTable writes the tag usage to stdout as a table
+func writeTagsTable(usage []tagUsage) error {
+	if len(usage) == 0 {
+		fmt.Println("no tags found")
+		return nil
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TAG\tENTRIES\tLAST USED")
+	for _, u := range usage {
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", u.tag, u.count, formatRecordDate(u.lastUsed))
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/tasks"
+	"github.com/spf13/cobra"
+)
+
+var (
+	todoEntryID string
+	todoSince   string
+	todoUntil   string
+	todoTopic   string
+	todoTags    []string
+	todoJSON    bool
+)
+
+// todoItem is an open task with the number of days it has been open
+type todoItem struct {
+	tasks.Item
+
+	// Days is the number of days since the task was first open
+	Days int `json:"days"`
+}
+
+var todoCmd = &cobra.Command{
+	Use:   "todo",
+	Short: "list the open tasks in journal entries",
+	Long: `List the open tasks ("- [ ]" items) in journal entries, grouped by entry and topic, oldest first.
+
+A task carried over from entry to entry is listed once, from the most recent entry it appears in,
+with the number of days since it first appeared. Use 'journal todo done <id>' to check it off.`,
+	Run: todoRun,
+}
+
+var todoDoneCmd = &cobra.Command{
+	Use:   "done <id>...",
+	Short: "mark open tasks as done",
+	Long: `Mark open tasks as done, by the IDs listed by 'journal todo'.
+
+The task is checked ("- [x]") in the file it was listed from, which is rewritten in place.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  todoDoneRun,
+}
+
+func init() {
+	todoCmd.Flags().StringVar(&todoEntryID, "id", "", "only list tasks in entries with this ID")
+	todoCmd.Flags().StringVar(&todoSince, "since", "", "only list tasks in entries dated on or after this date (e.g. 2024-08-02, last monday, -2w)")
+	todoCmd.Flags().StringVar(&todoUntil, "until", "", "only list tasks in entries dated on or before this date")
+	todoCmd.Flags().StringVar(&todoTopic, "topic", "", "only list tasks in entries whose topic contains this text")
+	todoCmd.Flags().StringSliceVar(&todoTags, "tag", nil, "only list tasks in entries with this tag (repeatable, or comma separated)")
+	todoCmd.Flags().BoolVar(&todoJSON, "json", false, "output the tasks as JSON")
+	todoCmd.AddCommand(todoDoneCmd)
+	rootCmd.AddCommand(todoCmd)
+}
+
+// todoRun is the run function for the todo command
+// It lists the open tasks of the indexed entries
+func todoRun(_ *cobra.Command, _ []string) {
+	items, err := collectTodoItems()
+	if err != nil {
+		logger.Log.Err(err).Msg("error collecting open tasks")
+		os.Exit(1)
+	}
+	if todoJSON {
+		err = writeTodoJSON(items)
+	} else {
+		err = writeTodoTable(items)
+	}
+	if err != nil {
+		logger.Log.Err(err).Msg("error writing output")
+		os.Exit(1)
+	}
+}
+
+// todoDoneRun is the run function for the todo done command
+// It checks the tasks with the given IDs in their files
+func todoDoneRun(_ *cobra.Command, ids []string) {
+	logger.Log.Debug().Strs("ids", ids).
+		Str("command", "todo done").
+		Msg("marking tasks as done with the 'todo done' command")
+
+	items, err := collectTodoItems()
+	if err != nil {
+		logger.Log.Err(err).Msg("error collecting open tasks")
+		os.Exit(1)
+	}
+	byID := map[string]todoItem{}
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			logger.Log.Error().Str("id", id).Msg("no open task with this ID")
+			os.Exit(1)
+		}
+		if err := completeTask(item); err != nil {
+			logger.Log.Err(err).Str("id", id).Msg("error marking task as done")
+			os.Exit(1)
+		}
+		fmt.Printf("done: %s (%s)\n", item.Text, item.Path)
+		updateIndex(item.Path)
+		commitRecord(item.Record)
+	}
+}
+
+// collectTodoItems returns the open tasks of the indexed entries matching the filter flags
+func collectTodoItems() ([]todoItem, error) {
+	filter, err := newRecordFilter(todoEntryID, todoSince, todoUntil, todoTopic, todoTags)
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Debug().Str("entry_id", filter.EntryID).
+		Time("since", filter.Since).
+		Time("until", filter.Until).
+		Str("topic", filter.Topic).
+		Strs("tags", filter.Tags).
+		Msg("collecting open tasks")
+
+	ix, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	found, err := tasks.Collect(filter.Apply(ix.Records()))
+	if err != nil {
+		return nil, err
+	}
+	items := []todoItem{}
+	for _, item := range found {
+		items = append(items, todoItem{Item: item, Days: daysSince(item.Since)})
+	}
+	return items, nil
+}
+
+// completeTask checks the task in its file
+func completeTask(item todoItem) error {
+	content, err := os.ReadFile(item.Path)
+	if err != nil {
+		return err
+	}
+	updated, err := tasks.Complete(string(content), item.Line, item.Text)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(item.Path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(item.Path, []byte(updated), info.Mode().Perm())
+}
+
+// daysSince returns the number of calendar days from the given date to the launch time
+func daysSince(date time.Time) int {
+	now := app.LaunchTime
+	if app.Location != nil {
+		now = now.In(app.Location)
+	}
+	date = date.In(now.Location())
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// formatDays formats a number of days for display (e.g. "today", "1 day", "12 days")
+func formatDays(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", days)
+	}
+}
+
+// writeTodoTable writes the tasks to stdout, under a heading for each entry ID and topic
+func writeTodoTable(items []todoItem) error {
+	if len(items) == 0 {
+		fmt.Println("no open tasks found")
+		return nil
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	group := ""
+	for _, item := range items {
+		heading := item.EntryID
+		if item.Topic != "" {
+			heading += " (" + item.Topic + ")"
+		}
+		if heading != group {
+			if group != "" {
+				fmt.Fprintln(writer)
+			}
+			fmt.Fprintln(writer, heading)
+			group = heading
+		}
+		fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\n", item.ID, formatDays(item.Days), item.Text, item.Path)
+	}
+	return writer.Flush()
+}
+
+// writeTodoJSON writes the tasks to stdout as a JSON array
+func writeTodoJSON(items []todoItem) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
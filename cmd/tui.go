@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/hooks"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiEditor string
+
+// tuiActions performs the operations of the terminal UI
+type tuiActions struct{}
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "browse and create journal entries in a full-screen terminal interface",
+	Long: `Browse and create journal entries in a full-screen terminal interface: a calendar marking the days
+with entries, the entries of the selected day, and a preview of the selected entry.
+
+Entries are opened in the configured editor; press n to create an entry of any configured type for the
+selected day.`,
+	Run: tuiRun,
+}
+
+func init() {
+	tuiCmd.Flags().StringVar(&tuiEditor, "editor", "", "editor to use for opening entries")
+	rootCmd.AddCommand(tuiCmd)
+}
+
+// tuiRun is the run function for the tui command
+// It runs the terminal UI until the user quits
+func tuiRun(_ *cobra.Command, _ []string) {
+	logger.Log.Debug().Str("editor", tuiEditor).
+		Str("command", "tui").
+		Msg("starting the terminal UI with the 'tui' command")
+
+	actions := tuiActions{}
+	records, err := actions.Records()
+	if err != nil {
+		logger.Log.Err(err).Msg("error loading index")
+		os.Exit(1)
+	}
+	entryIDs := []string{}
+	for _, entry := range app.Config.Entries {
+		entryIDs = append(entryIDs, entry.ID)
+	}
+	sort.Strings(entryIDs)
+
+	// Output from hooks would corrupt the screen, so it is discarded (failing hooks are still reported)
+	hookRunner = hooks.Runner{Stdout: io.Discard, Stderr: io.Discard}
+
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		logger.Log.Err(err).Msg("error opening terminal")
+		os.Exit(1)
+	}
+	model := tui.NewModel(app.LaunchTime, entryIDs, records)
+	if err := tui.New(screen, model, actions).Run(); err != nil {
+		logger.Log.Err(err).Msg("error running terminal UI")
+		os.Exit(1)
+	}
+}
+
+// Records returns the indexed entries
+func (tuiActions) Records() ([]catalog.Record, error) {
+	ix, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	return ix.Records(), nil
+}
+
+// Open opens an entry's file in the editor configured for its entry type
+func (tuiActions) Open(record catalog.Record) error {
+	entryApp, err := recordApp(record)
+	if err != nil {
+		return err
+	}
+	// Encrypted files are only decrypted until the editor closes them
+	if err := entryApp.SetEditorWait(record.Encrypted); err != nil {
+		return err
+	}
+	if err := entryApp.SetEditor(tuiEditor); err != nil {
+		return err
+	}
+	editor, err := entryApp.GetEditor()
+	if err != nil {
+		return err
+	}
+	if err := openEntryFile(entryApp, editor, record.Path, nil); err != nil {
+		return err
+	}
+	updateIndex(record.Path)
+	if editor.Blocks() {
+		// Otherwise the editor may still be open, so the edits are left for 'journal sync' to commit
+		commitEntry(entryApp, record.Path)
+	}
+	return nil
+}
+
+// Create creates an entry of the given type for the given day (at the current time of day)
+func (tuiActions) Create(entryID string, day time.Time) (string, error) {
+	now := time.Now().In(day.Location())
+	entryTime := time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), now.Second(), 0, day.Location())
+	return createDerivedEntry(entryID, entryTime, "", nil)
+}
@@ -2,14 +2,24 @@ module github.com/matthewchivers/journal
 
 go 1.22.1
 
-require github.com/spf13/cobra v1.8.1
+require (
+	github.com/gdamore/tcell/v2 v2.7.4
+	github.com/spf13/cobra v1.8.1
+	github.com/yuin/goldmark v1.7.8
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/gdamore/encoding v1.0.0 // indirect
+	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mattn/go-runewidth v0.0.15 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rivo/uniseg v0.4.3 // indirect
 	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/term v0.17.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
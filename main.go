@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	_ "time/tzdata" // embed the IANA timezone database, for systems without one
 
 	"github.com/matthewchivers/journal/cmd"
 )
@@ -13,6 +13,15 @@ type App struct {
 	// LaunchTime is the time the application was launched
 	LaunchTime time.Time
 
+	// Timezone is the name of the IANA timezone the application operates in (empty for the local timezone)
+	Timezone string
+
+	// Location is the location for the timezone, used to calculate dates
+	Location *time.Location
+
+	// timezoneOverride is the timezone specified on the command line (takes precedence over the configuration)
+	timezoneOverride string
+
 	// ConfigPath is the path to the configuration file
 	ConfigPath string
 
@@ -34,6 +43,27 @@ type App struct {
 	// FilePath is the full path to the file
 	FilePath string
 
+	// IndexPath is the path to the index of entries
+	IndexPath string
+
+	// KeyPath is the path to the key encrypted entries are encrypted with
+	KeyPath string
+
+	// Encrypt is true if the entry's file is encrypted
+	Encrypt bool
+
+	// TemplatesDirectory is the directory containing document templates
+	TemplatesDirectory string
+
+	// TemplateName is the name of the document template used to populate the new file
+	TemplateName string
+
+	// Tags are the tags of the entry
+	Tags []string
+
+	// OnExists is the policy applied when the file for the entry already exists (see config.OnExists*)
+	OnExists string
+
 	// TemplateData is the data used to populate the templating patterns
 	TemplateData *templating.TemplateModel
 
@@ -43,6 +73,9 @@ type App struct {
 	// Editor is the ID of the editor to use
 	Editor string
 
+	// EditorWait is true if the editor should block until the file is closed
+	EditorWait bool
+
 	// targetEditor is the editor to use
 	targetEditor editor.Editor
 }
@@ -0,0 +1,124 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/tasks"
+)
+
+// carryOverField is the template field the carried over tasks are rendered with
+const carryOverField = ".CarryOver"
+
+// UsesCarryOver reports whether the entry's document template or front matter refers to {{.CarryOver}},
+// so that the previous entry is only looked up when its tasks are used
+func (app *App) UsesCarryOver() bool {
+	if entry, err := app.GetTargetEntry(); err == nil {
+		for _, item := range entry.FrontMatter {
+			if strings.Contains(fmt.Sprint(item.Value), carryOverField) {
+				return true
+			}
+		}
+	}
+	if app.TemplateName == "" || app.TemplatesDirectory == "" {
+		return false
+	}
+	content, err := os.ReadFile(filepath.Join(app.TemplatesDirectory, app.TemplateName))
+	return err == nil && strings.Contains(string(content), carryOverField)
+}
+
+// SetCarryOver sets the unfinished tasks ("- [ ]" items) of the previous file of the entry type, so they can be
+// carried over to the new file with {{.CarryOver}}
+// The previous file is found in the indexed records (see PreviousEntry)
+// If there is no previous file (e.g. the entry's patterns contain no date), there is nothing to carry over
+// Encrypted files are decrypted to find their tasks
+func (app *App) SetCarryOver(indexed []catalog.Record) error {
+	if app.TemplateData == nil {
+		return errors.New("pattern data must be initialised before setting carry-over tasks")
+	}
+	previous, ok, err := app.PreviousEntry(indexed)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		logger.Log.Debug().Str("entry_id", app.EntryID).Msg("no previous entry to carry tasks over from")
+		return nil
+	}
+	content, err := app.ReadEntryFile(previous.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read previous entry: %w", err)
+	}
+	open := tasks.Open(string(content))
+	app.TemplateData.CarryOver = tasks.List(open)
+	logger.Log.Debug().Str("previous_entry", previous.Path).
+		Int("tasks", len(open)).
+		Msg("unfinished tasks carried over")
+	return nil
+}
+
+// PreviousEntry finds the most recent existing file of the entry type in the base directory, dated before the
+// entry
+// The indexed records (of the configured base directories) are used, unless the base directory has been
+// overridden, in which case it is scanned with the entry's directory and file name patterns
+func (app *App) PreviousEntry(indexed []catalog.Record) (catalog.Record, bool, error) {
+	if app.Config == nil {
+		return catalog.Record{}, false, errors.New("config must be loaded before finding the previous entry")
+	}
+	if app.LaunchTime.IsZero() {
+		return catalog.Record{}, false, errors.New("launch time must be set before finding the previous entry")
+	}
+	if app.BaseDirectory == "" {
+		return catalog.Record{}, false, errors.New("base directory must be set before finding the previous entry")
+	}
+	records, err := app.baseDirectoryRecords(indexed)
+	if err != nil {
+		return catalog.Record{}, false, fmt.Errorf("failed to find previous entry: %w", err)
+	}
+	entryDate := calendarDate(app.LaunchTime)
+	baseDirectory := filepath.Clean(app.BaseDirectory) + string(filepath.Separator)
+
+	// Records are sorted by date, so the last one before the entry's date is the most recent
+	previous, found := catalog.Record{}, false
+	for _, record := range records {
+		if record.EntryID != app.EntryID || record.Date.IsZero() || !strings.HasPrefix(record.Path, baseDirectory) {
+			continue
+		}
+		if calendarDate(record.Date).Before(entryDate) {
+			previous, found = record, true
+		}
+	}
+	return previous, found, nil
+}
+
+// baseDirectoryRecords returns the indexed records, or the records found by scanning the base directory if it is
+// not the entry's configured base directory (and so is not indexed)
+func (app *App) baseDirectoryRecords(indexed []catalog.Record) ([]catalog.Record, error) {
+	entry, err := app.GetTargetEntry()
+	if err != nil {
+		return nil, err
+	}
+	configured := app.Config.Paths.BaseDirectory
+	if entry.BaseDirectory != "" {
+		configured = entry.BaseDirectory
+	}
+	if filepath.Clean(configured) == filepath.Clean(app.BaseDirectory) {
+		return indexed, nil
+	}
+	scanned := *entry
+	scanned.BaseDirectory = app.BaseDirectory
+	cfg := &config.Config{Entries: []config.Entry{scanned}}
+	return catalog.Scan(cfg, app.LaunchTime.Location())
+}
+
+// calendarDate returns the date of a time (at midnight UTC), so that dates in different timezones can be compared
+func calendarDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
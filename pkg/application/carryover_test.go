@@ -0,0 +1,158 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/encryption"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestSetCarryOver(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	baseDir := t.TempDir()
+	files := map[string]string{
+		"standup/2024-07-31.md": "- [ ] old task\n",
+		"standup/2024-08-01.md": "# Stand-up\n- [x] finished\n- [ ] write report\n  - [ ] nested\n",
+		"standup/2024-08-02.md": "- [ ] today's task\n",
+		"review/2024-08-01.md":  "- [ ] another entry type\n",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(baseDir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0750))
+		assert.NoError(t, os.WriteFile(filePath, []byte(content), 0600))
+	}
+	keyPath := filepath.Join(t.TempDir(), "journal.key")
+	otherBaseDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(otherBaseDir, "standup"), 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(otherBaseDir, "standup/2024-08-01.md"), []byte("- [ ] elsewhere\n"), 0600))
+	templatesDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(templatesDir, "tasks.md"), []byte("{{.CarryOver}}"), 0600))
+	key, _, err := encryption.LoadOrCreateKey(keyPath)
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Join(baseDir, "oneonone"), 0750))
+	assert.NoError(t, encryption.WriteFile(key, filepath.Join(baseDir, "oneonone/2024-08-01.md.enc"),
+		[]byte("- [ ] private task\n")))
+	cfg := &config.Config{
+		Paths: config.Paths{BaseDirectory: baseDir},
+		Entries: []config.Entry{
+			{ID: "standup", DirectoryPattern: "{{.EntryID}}", FileNamePattern: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+				TemplateName: "tasks.md"},
+			{ID: "review", DirectoryPattern: "{{.EntryID}}", FileNamePattern: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+				TemplateName: "tasks.md"},
+			{ID: "oneonone", DirectoryPattern: "{{.EntryID}}", FileNamePattern: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+				TemplateName: "tasks.md", Encrypt: true},
+		},
+	}
+	// The records of the configured base directory stand in for the index
+	indexed, err := catalog.Scan(cfg, time.UTC)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		entryID  string
+		baseDir  string
+		date     time.Time
+		want     string
+		wantPath string
+	}{
+		{
+			name:     "previous day",
+			entryID:  "standup",
+			date:     time.Date(2024, time.August, 2, 9, 30, 0, 0, time.UTC),
+			want:     "- [ ] write report\n- [ ] nested\n",
+			wantPath: filepath.Join(baseDir, "standup/2024-08-01.md"),
+		},
+		{
+			name:     "after a gap",
+			entryID:  "standup",
+			date:     time.Date(2024, time.August, 5, 9, 30, 0, 0, time.UTC),
+			want:     "- [ ] today's task\n",
+			wantPath: filepath.Join(baseDir, "standup/2024-08-02.md"),
+		},
+		{
+			name:     "encrypted entry",
+			entryID:  "oneonone",
+			date:     time.Date(2024, time.August, 8, 9, 30, 0, 0, time.UTC),
+			want:     "- [ ] private task\n",
+			wantPath: filepath.Join(baseDir, "oneonone/2024-08-01.md.enc"),
+		},
+		{
+			name:     "base directory overridden",
+			entryID:  "standup",
+			baseDir:  otherBaseDir,
+			date:     time.Date(2024, time.August, 2, 9, 30, 0, 0, time.UTC),
+			want:     "- [ ] elsewhere\n",
+			wantPath: filepath.Join(otherBaseDir, "standup/2024-08-01.md"),
+		},
+		{
+			name:    "no previous entry",
+			entryID: "standup",
+			date:    time.Date(2024, time.July, 31, 9, 30, 0, 0, time.UTC),
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{Config: cfg, KeyPath: keyPath, TemplatesDirectory: templatesDir}
+			app.SetLaunchTime(tt.date)
+			assert.NoError(t, app.SetEntryID(tt.entryID))
+			assert.NoError(t, app.PreparePatternData())
+			assert.NoError(t, app.SetBaseDirectory(tt.baseDir))
+			assert.NoError(t, app.SetTemplateName(""))
+			assert.True(t, app.UsesCarryOver())
+
+			previous, ok, err := app.PreviousEntry(indexed)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPath != "", ok)
+			assert.Equal(t, tt.wantPath, previous.Path)
+
+			assert.NoError(t, app.SetCarryOver(indexed))
+			assert.Equal(t, tt.want, app.TemplateData.CarryOver)
+		})
+	}
+}
+
+func TestUsesCarryOver(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	templatesDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(templatesDir, "tasks.md"), []byte("## Carried Over\n{{.CarryOver}}"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(templatesDir, "notes.md"), []byte("# {{.Day.Name}}\n"), 0600))
+	cfg := &config.Config{
+		Entries: []config.Entry{
+			{ID: "tasks", TemplateName: "tasks.md"},
+			{ID: "notes", TemplateName: "notes.md"},
+			{ID: "frontmatter", FrontMatter: yaml.MapSlice{{Key: "carried", Value: "{{.CarryOver}}"}}},
+			{ID: "empty"},
+		},
+	}
+
+	tests := []struct {
+		entryID string
+		want    bool
+	}{
+		{entryID: "tasks", want: true},
+		{entryID: "notes", want: false},
+		{entryID: "frontmatter", want: true},
+		{entryID: "empty", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entryID, func(t *testing.T) {
+			app := &App{Config: cfg, TemplatesDirectory: templatesDir}
+			assert.NoError(t, app.SetEntryID(tt.entryID))
+			assert.NoError(t, app.SetTemplateName(""))
+			assert.Equal(t, tt.want, app.UsesCarryOver())
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"errors"
+	"time"
+)
+
+// DeriveEntry returns a new App for the given entry at the given time, sharing this app's configuration
+// The derived app has its pattern data, paths and document template prepared from the entry configuration,
+// so it can be used to reason about (or create) entries other than the one targeted by this app
+// The date and time of day of entryTime are kept as they are in the entry's timezone
+// (e.g. a backfilled entry for the 2nd of August is dated the 2nd in whichever timezone the entry uses)
+func (app *App) DeriveEntry(entryID string, entryTime time.Time) (*App, error) {
+	if app.Config == nil {
+		return nil, errors.New("config must be loaded before deriving an entry")
+	}
+	derived, err := NewApp()
+	if err != nil {
+		return nil, err
+	}
+	derived.ConfigPath = app.ConfigPath
+	derived.Config = app.Config
+	derived.KeyPath = app.KeyPath
+	derived.SetLaunchTime(entryTime)
+
+	if err := derived.SetEntryID(entryID); err != nil {
+		return nil, err
+	}
+	if err := derived.SetTimezone(app.timezoneOverride); err != nil {
+		return nil, err
+	}
+	derived.SetLaunchTime(time.Date(entryTime.Year(), entryTime.Month(), entryTime.Day(),
+		entryTime.Hour(), entryTime.Minute(), entryTime.Second(), entryTime.Nanosecond(), derived.Location))
+	if err := derived.PreparePatternData(); err != nil {
+		return nil, err
+	}
+	if err := derived.setEntryValues(app.TemplatesDirectory); err != nil {
+		return nil, err
+	}
+	return derived, nil
+}
+
+// setEntryValues sets the values of a derived entry from its configuration
+func (app *App) setEntryValues(templatesDirectory string) error {
+	if err := app.SetTopic(""); err != nil {
+		return err
+	}
+	if err := app.SetTags(nil); err != nil {
+		return err
+	}
+	if err := app.SetFileExtension(""); err != nil {
+		return err
+	}
+	if err := app.SetBaseDirectory(""); err != nil {
+		return err
+	}
+	if err := app.SetTemplatesDirectory(templatesDirectory); err != nil {
+		return err
+	}
+	if err := app.SetTemplateName(""); err != nil {
+		return err
+	}
+	if err := app.SetOnExists(""); err != nil {
+		return err
+	}
+
+	// FileName and EntryDirectory depend on other values being set - call them last
+	if err := app.SetFileName(""); err != nil {
+		return err
+	}
+	return app.SetEntryDirectory("")
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/matthewchivers/journal/pkg/digest"
+	"github.com/matthewchivers/journal/pkg/logger"
+)
+
+// GetDigestContent renders the content of a digest file for the entry: its front matter (see GetFrontMatter),
+// followed by the digest rendered with the configured digest template (or the built-in template)
+func (app *App) GetDigestContent(model *digest.Model) (string, error) {
+	name, document := "digest", digest.DefaultTemplate
+	if app.Config != nil && app.Config.Digest.TemplateName != "" {
+		name = app.Config.Digest.TemplateName
+		templatePath := filepath.Join(app.TemplatesDirectory, name)
+		templateContent, err := os.ReadFile(templatePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read digest template: %w", err)
+		}
+		document = string(templateContent)
+	}
+	rendered, err := model.Render(name, document)
+	if err != nil {
+		return "", fmt.Errorf("failed to render digest template: %w", err)
+	}
+	logger.Log.Debug().Str("template", name).
+		Int("entries", len(model.Entries)).
+		Int("document_length", len(rendered)).
+		Msg("digest rendered")
+
+	frontMatter, err := app.GetFrontMatter()
+	if err != nil {
+		return "", err
+	}
+	return frontMatter + rendered, nil
+}
@@ -2,40 +2,68 @@ package application
 
 import (
 	"errors"
+	"os"
 
 	"github.com/matthewchivers/journal/pkg/editor"
+	"github.com/matthewchivers/journal/pkg/logger"
 )
 
-// SetEditor sets the editor ID for the entry
-// If editor is empty, the default editor is used
+// SetEditor sets the editor for the entry
+// If editorID is empty, the editor is taken from the entry configuration, then the default editor,
+// then the $VISUAL and $EDITOR environment variables
+// An editor is either the name of a built-in editor (e.g. "vscode", "vim") or a command template
 func (app *App) SetEditor(editorID string) error {
 	if app.targetEntry == nil {
 		return errors.New("entry must be set before setting editor ID")
 	}
-	if editorID != "" {
-		app.Editor = editorID
-	}
-	app.Editor = app.Config.Editor
-	if app.targetEntry.Editor != "" {
+	app.Editor = editorID
+	if app.Editor == "" {
 		app.Editor = app.targetEntry.Editor
 	}
-
-	switch app.Editor {
-	case "":
-		return errors.New("editor not set")
-	case "vscode":
-		vscEditor, err := editor.NewVSCodeEditor()
-		if err != nil {
-			return err
+	if app.Editor == "" {
+		app.Editor = app.Config.Editor
+	}
+	for _, envVar := range []string{"VISUAL", "EDITOR"} {
+		if app.Editor == "" {
+			app.Editor = os.Getenv(envVar)
 		}
-		app.targetEditor = vscEditor
-	default:
-		return errors.New("editor not supported")
 	}
+	if app.Editor == "" {
+		return errors.New("editor not set")
+	}
+
+	targetEditor, err := editor.NewEditor(app.Editor, app.EditorWait)
+	if err != nil {
+		return err
+	}
+	app.targetEditor = targetEditor
+	logger.Log.Debug().Str("editor", app.Editor).
+		Bool("wait", app.EditorWait).
+		Msg("editor set")
 
 	return nil
 }
 
+// SetEditorWait sets whether the editor should block until the file is closed
+// If wait is false, the setting is taken from the entry configuration, then the default configuration
+// Encrypted entries always wait, as the decrypted file is only kept until the editor closes it
+// Must be called before SetEditor
+func (app *App) SetEditorWait(wait bool) error {
+	if app.targetEntry == nil {
+		return errors.New("entry must be set before setting editor wait")
+	}
+	app.EditorWait = wait
+	if !app.EditorWait {
+		if app.targetEntry.EditorWait != nil {
+			app.EditorWait = *app.targetEntry.EditorWait
+		} else {
+			app.EditorWait = app.Config.EditorWait
+		}
+	}
+	app.EditorWait = app.EditorWait || app.Encrypt
+	return nil
+}
+
 // GetEditor returns the editor for the entry
 func (app *App) GetEditor() (editor.Editor, error) {
 	if app.targetEditor == nil {
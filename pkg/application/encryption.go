@@ -0,0 +1,40 @@
+package application
+
+import (
+	"errors"
+	"os"
+
+	"github.com/matthewchivers/journal/pkg/encryption"
+	"github.com/matthewchivers/journal/pkg/logger"
+)
+
+// GetEncryptionKey loads the key encrypted entries are encrypted with
+// If create is true and there is no key yet, a new key is generated and saved at the key path
+func (app *App) GetEncryptionKey(create bool) ([]byte, error) {
+	if app.KeyPath == "" {
+		return nil, errors.New("key path must be set before getting the encryption key")
+	}
+	if !create {
+		return encryption.LoadKey(app.KeyPath)
+	}
+	key, created, err := encryption.LoadOrCreateKey(app.KeyPath)
+	if created {
+		logger.Log.Warn().Str("key_path", app.KeyPath).
+			Msg("created a new encryption key - back it up, as encrypted entries cannot be read without it")
+	}
+	return key, err
+}
+
+// ReadEntryFile reads the content of an entry's file, decrypting it if it is encrypted
+func (app *App) ReadEntryFile(filePath string) ([]byte, error) {
+	if !encryption.IsEncrypted(filePath) {
+		// #nosec G304: Potential file inclusion via variable
+		// The file path is that of an existing entry, found by the application
+		return os.ReadFile(filePath)
+	}
+	key, err := app.GetEncryptionKey(false)
+	if err != nil {
+		return nil, err
+	}
+	return encryption.ReadFile(key, filePath)
+}
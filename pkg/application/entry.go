@@ -12,13 +12,11 @@ import (
 
 // SetEntryID sets the entry ID for the context
 // If entryID is empty, the default entry is used
+// May be called before or after the pattern data is prepared
 func (app *App) SetEntryID(entryID string) error {
 	if app.Config == nil {
 		return errors.New("config must be loaded before setting entry ID")
 	}
-	if app.TemplateData == nil {
-		return errors.New("pattern data must be initialised before setting entry id")
-	}
 	if entryID != "" {
 		app.EntryID = strings.ToLower(entryID)
 	} else {
@@ -27,11 +25,16 @@ func (app *App) SetEntryID(entryID string) error {
 	if app.EntryID == "" {
 		return errors.New("no entry specified")
 	}
-	if _, err := app.Config.FetchEntryByID(app.EntryID); err != nil {
+	entry, err := app.Config.FetchEntryByID(app.EntryID)
+	if err != nil {
 		return err
 	}
 
-	app.TemplateData.EntryID = app.EntryID
+	app.targetEntry = entry
+	app.Encrypt = entry.Encrypt
+	if app.TemplateData != nil {
+		app.TemplateData.EntryID = app.EntryID
+	}
 
 	return nil
 }
@@ -0,0 +1,27 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/matthewchivers/journal/pkg/config"
+)
+
+// SetOnExists sets the policy applied when the file for the entry already exists
+// If policy is empty, the policy is taken from the entry configuration, then the default configuration,
+// falling back to opening the existing file
+func (app *App) SetOnExists(policy string) error {
+	if app.targetEntry == nil {
+		return errors.New("entry must be set before setting onExists policy")
+	}
+	app.OnExists = policy
+	if app.OnExists == "" {
+		app.OnExists = app.targetEntry.OnExists
+	}
+	if app.OnExists == "" {
+		app.OnExists = app.Config.OnExists
+	}
+	if app.OnExists == "" {
+		app.OnExists = config.OnExistsOpen
+	}
+	return config.ValidateOnExists(app.OnExists)
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/frontmatter"
+	"github.com/matthewchivers/journal/pkg/tags"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// markdownExtensions are the file extensions that front matter is added to
+var markdownExtensions = map[string]bool{
+	"md":       true,
+	"markdown": true,
+}
+
+// GetFrontMatter renders the front matter configured for the entry (including the "---" delimiters)
+// The entry's tags are added to the tags field (which is added if the entry has tags but none is configured)
+// Front matter is only rendered for Markdown files; otherwise (or if there are no fields) it is empty
+func (app *App) GetFrontMatter() (string, error) {
+	entry, err := app.GetTargetEntry()
+	if err != nil {
+		return "", err
+	}
+	if app.TemplateData == nil {
+		return "", errors.New("pattern data must be initialised before rendering front matter")
+	}
+	if !markdownExtensions[strings.ToLower(app.TemplateData.FileExtension)] {
+		return "", nil
+	}
+
+	fields := frontmatter.Fields{}
+	hasTags := false
+	for _, item := range entry.FrontMatter {
+		key := fmt.Sprint(item.Key)
+		value, err := app.renderFrontMatterValue(key, item.Value)
+		if err != nil {
+			return "", fmt.Errorf("failed to render front matter field %q: %w", key, err)
+		}
+		if key == tags.FrontMatterKey {
+			value = app.mergeTags(value)
+			hasTags = true
+		}
+		fields = append(fields, yaml.MapItem{Key: key, Value: value})
+	}
+	if !hasTags && len(app.Tags) > 0 {
+		fields = append(fields, yaml.MapItem{Key: tags.FrontMatterKey, Value: app.Tags})
+	}
+	return frontmatter.Render(fields)
+}
+
+// mergeTags merges the entry's tags into the (rendered) value of the tags field
+func (app *App) mergeTags(value interface{}) []string {
+	fieldTags := frontmatter.Fields{{Key: tags.FrontMatterKey, Value: value}}.Strings(tags.FrontMatterKey)
+	merged := tags.Merge(fieldTags, app.Tags)
+	if merged == nil {
+		return []string{}
+	}
+	return merged
+}
+
+// renderFrontMatterValue renders a front matter value: strings (and lists of strings) are patterns,
+// other values (e.g. numbers and booleans) are used as they are
+func (app *App) renderFrontMatterValue(key string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return app.TemplateData.ParseDocument(key, v)
+	case []interface{}:
+		values := []interface{}{}
+		for _, item := range v {
+			rendered, err := app.renderFrontMatterValue(key, item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, rendered)
+		}
+		return values, nil
+	default:
+		return v, nil
+	}
+}
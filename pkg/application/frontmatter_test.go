@@ -0,0 +1,101 @@
+package application
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestGetFrontMatter(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	frontMatter := yaml.MapSlice{
+		{Key: "date", Value: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}"},
+		{Key: "entry", Value: "{{.EntryID}}"},
+		{Key: "topic", Value: "{{.Topic}} & more"},
+		{Key: "tags", Value: []interface{}{"{{.EntryID}}", "work"}},
+		{Key: "draft", Value: true},
+	}
+	tests := []struct {
+		name          string
+		fileExtension string
+		frontMatter   yaml.MapSlice
+		tags          []string
+		want          string
+		wantErr       bool
+	}{
+		{
+			name:          "markdown file",
+			fileExtension: "md",
+			frontMatter:   frontMatter,
+			want: "---\ndate: \"2024-08-02\"\nentry: meeting\ntopic: planning & more\n" +
+				"tags:\n- meeting\n- work\ndraft: true\n---\n",
+		},
+		{
+			name:          "tags merged into the tags field",
+			fileExtension: "md",
+			frontMatter:   frontMatter,
+			tags:          []string{"Client X", "work"},
+			want: "---\ndate: \"2024-08-02\"\nentry: meeting\ntopic: planning & more\n" +
+				"tags:\n- client-x\n- meeting\n- work\ndraft: true\n---\n",
+		},
+		{
+			name:          "tags field added",
+			fileExtension: "md",
+			frontMatter:   yaml.MapSlice{{Key: "entry", Value: "{{.EntryID}}"}},
+			tags:          []string{"work"},
+			want:          "---\nentry: meeting\ntags:\n- work\n---\n",
+		},
+		{
+			name:          "only tags",
+			fileExtension: "md",
+			tags:          []string{"work"},
+			want:          "---\ntags:\n- work\n---\n",
+		},
+		{
+			name:          "not a markdown file",
+			fileExtension: "txt",
+			frontMatter:   frontMatter,
+			want:          "",
+		},
+		{
+			name:          "no front matter",
+			fileExtension: "md",
+			want:          "",
+		},
+		{
+			name:          "invalid pattern",
+			fileExtension: "md",
+			frontMatter:   yaml.MapSlice{{Key: "date", Value: "{{.Year.Num"}},
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{Config: &config.Config{
+				Entries: []config.Entry{{ID: "meeting", FrontMatter: tt.frontMatter}},
+			}}
+			app.SetLaunchTime(time.Date(2024, time.August, 2, 9, 30, 0, 0, time.UTC))
+			assert.NoError(t, app.SetEntryID("meeting"))
+			assert.NoError(t, app.PreparePatternData())
+			assert.NoError(t, app.SetTopic("planning"))
+			assert.NoError(t, app.SetTags(tt.tags))
+			assert.NoError(t, app.SetFileExtension(tt.fileExtension))
+
+			got, err := app.GetFrontMatter()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/templating"
+)
+
+// GetCommitMessage renders the message for committing the entry to git, from the configured commit message
+// pattern (or the default pattern)
+func (app *App) GetCommitMessage() (string, error) {
+	if app.TemplateData == nil {
+		return "", errors.New("pattern data must be initialised before rendering the commit message")
+	}
+	pattern := config.DefaultCommitMessage
+	if app.Config != nil && app.Config.Git.CommitMessage != "" {
+		pattern = app.Config.Git.CommitMessage
+	}
+	message, err := templating.RenderDocument("commitMessage", pattern, app.TemplateData)
+	if err != nil {
+		return "", fmt.Errorf("failed to render commit message: %w", err)
+	}
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return "", errors.New("commit message is empty")
+	}
+	return message, nil
+}
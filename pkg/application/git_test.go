@@ -0,0 +1,70 @@
+package application
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCommitMessage(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	tests := []struct {
+		name    string
+		pattern string
+		topic   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default pattern",
+			want: "Update meeting entry for 2024-08-02",
+		},
+		{
+			name:  "default pattern with topic",
+			topic: "planning & review",
+			want:  "Update meeting entry for 2024-08-02 (planning & review)",
+		},
+		{
+			name:    "configured pattern",
+			pattern: "journal: {{.EntryID}} {{.Month.Name}} {{.Day.Ord}}\n",
+			want:    "journal: meeting August 2nd",
+		},
+		{
+			name:    "empty message",
+			pattern: "{{.Topic}}",
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			pattern: "{{.Missing}}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{Config: &config.Config{
+				Entries: []config.Entry{{ID: "meeting"}},
+				Git:     config.Git{Enabled: true, CommitMessage: tt.pattern},
+			}}
+			app.SetLaunchTime(time.Date(2024, time.August, 2, 9, 30, 0, 0, time.UTC))
+			assert.NoError(t, app.SetEntryID("meeting"))
+			assert.NoError(t, app.PreparePatternData())
+			assert.NoError(t, app.SetTopic(tt.topic))
+
+			got, err := app.GetCommitMessage()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/hooks"
+)
+
+// GetHooks returns the commands to run for the event: the default hooks, followed by the entry's hooks
+func (app *App) GetHooks(event hooks.Event) ([]string, error) {
+	entry, err := app.GetTargetEntry()
+	if err != nil {
+		return nil, err
+	}
+	commands := []string{}
+	for _, h := range []config.Hooks{app.Config.Hooks, entry.Hooks} {
+		switch event {
+		case hooks.PreCreate:
+			commands = append(commands, h.PreCreate...)
+		case hooks.PostCreate:
+			commands = append(commands, h.PostCreate...)
+		case hooks.PostEdit:
+			commands = append(commands, h.PostEdit...)
+		default:
+			return nil, errors.New("unknown hook event: " + string(event))
+		}
+	}
+	return commands, nil
+}
+
+// RunHooks runs the hooks for the event, describing the entry's file at filePath to them
+// created is true if the file was created (rather than already existing)
+func (app *App) RunHooks(runner hooks.Runner, event hooks.Event, filePath string, created bool) error {
+	if app.TemplateData == nil {
+		return errors.New("pattern data must be initialised before running hooks")
+	}
+	commands, err := app.GetHooks(event)
+	if err != nil {
+		return err
+	}
+	return runner.Run(commands, hooks.NewPayload(event, filePath, *app.TemplateData, created))
+}
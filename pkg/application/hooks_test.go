@@ -0,0 +1,54 @@
+package application
+
+import (
+	"os"
+	"testing"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/hooks"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHooks(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	app := &App{Config: &config.Config{
+		Hooks: config.Hooks{
+			PreCreate:  []string{"check-disk"},
+			PostCreate: []string{"notify"},
+		},
+		Entries: []config.Entry{
+			{ID: "meeting", Hooks: config.Hooks{PreCreate: []string{"check-calendar"}, PostEdit: []string{"sync-notes"}}},
+			{ID: "standup"},
+		},
+	}}
+
+	tests := []struct {
+		name    string
+		entryID string
+		event   hooks.Event
+		want    []string
+		wantErr bool
+	}{
+		{name: "default then entry hooks", entryID: "meeting", event: hooks.PreCreate, want: []string{"check-disk", "check-calendar"}},
+		{name: "default hooks only", entryID: "meeting", event: hooks.PostCreate, want: []string{"notify"}},
+		{name: "entry hooks only", entryID: "meeting", event: hooks.PostEdit, want: []string{"sync-notes"}},
+		{name: "no hooks", entryID: "standup", event: hooks.PostEdit, want: []string{}},
+		{name: "unknown event", entryID: "standup", event: "preEdit", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, app.SetEntryID(tt.entryID))
+			got, err := app.GetHooks(tt.event)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"path/filepath"
 
+	"github.com/matthewchivers/journal/pkg/encryption"
 	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/paths"
 )
 
 // SetBaseDirectory sets the base directory for the entry
@@ -24,8 +26,78 @@ func (app *App) SetBaseDirectory(baseDir string) error {
 	return nil
 }
 
+// SetTemplatesDirectory sets the directory containing document templates
+// If templatesDir is empty, the configured directory is used (default: ~/.journal/templates)
+func (app *App) SetTemplatesDirectory(templatesDir string) error {
+	if templatesDir == "" && app.Config != nil {
+		templatesDir = app.Config.Paths.TemplatesDirectory
+	}
+	if templatesDir == "" {
+		appHome, err := paths.GetAppHomePath()
+		if err != nil {
+			return err
+		}
+		templatesDir = filepath.Join(appHome, "templates")
+	}
+	expandedDir, err := paths.ExpandHome(templatesDir)
+	if err != nil {
+		return err
+	}
+	app.TemplatesDirectory = expandedDir
+	logger.Log.Debug().Str("templates_directory", app.TemplatesDirectory).
+		Msg("templates directory set")
+	return nil
+}
+
+// SetIndexPath sets the path to the index of entries
+// If indexPath is empty, the configured path is used (default: ~/.journal/index.json)
+func (app *App) SetIndexPath(indexPath string) error {
+	if indexPath == "" && app.Config != nil {
+		indexPath = app.Config.Paths.IndexPath
+	}
+	if indexPath == "" {
+		appHome, err := paths.GetAppHomePath()
+		if err != nil {
+			return err
+		}
+		indexPath = filepath.Join(appHome, "index.json")
+	}
+	expandedPath, err := paths.ExpandHome(indexPath)
+	if err != nil {
+		return err
+	}
+	app.IndexPath = expandedPath
+	logger.Log.Debug().Str("index_path", app.IndexPath).
+		Msg("index path set")
+	return nil
+}
+
+// SetKeyPath sets the path to the key encrypted entries are encrypted with
+// If keyPath is empty, the configured path is used (default: ~/.journal/journal.key)
+func (app *App) SetKeyPath(keyPath string) error {
+	if keyPath == "" && app.Config != nil {
+		keyPath = app.Config.Paths.KeyPath
+	}
+	if keyPath == "" {
+		appHome, err := paths.GetAppHomePath()
+		if err != nil {
+			return err
+		}
+		keyPath = filepath.Join(appHome, "journal.key")
+	}
+	expandedPath, err := paths.ExpandHome(keyPath)
+	if err != nil {
+		return err
+	}
+	app.KeyPath = expandedPath
+	logger.Log.Debug().Str("key_path", app.KeyPath).
+		Msg("key path set")
+	return nil
+}
+
 // SetFileName sets the file name for the entry
 // If fileName is empty, the default file name is retrieved
+// The names of encrypted entries are given the encryption extension (e.g. review.md.enc)
 func (app *App) SetFileName(fileName string) error {
 	if app.TemplateData == nil {
 		return errors.New("pattern data must be initialised before setting file name")
@@ -39,6 +111,9 @@ func (app *App) SetFileName(fileName string) error {
 		}
 		app.FileName = fileName
 	}
+	if app.Encrypt && !encryption.IsEncrypted(app.FileName) {
+		app.FileName += encryption.Extension
+	}
 	logger.Log.Debug().Str("file_name_override", fileName).
 		Str("file_name_final", app.FileName).
 		Msg("file name set")
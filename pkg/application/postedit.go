@@ -0,0 +1,52 @@
+package application
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/frontmatter"
+	"github.com/matthewchivers/journal/pkg/logger"
+)
+
+// PostEdit runs the post-edit steps for a file once the editor has closed it
+// initialContent is the content the file was created with, created is true if the file was created
+// by this run (rather than already existing), and opened is the time the file was opened
+// A newly created file that is still identical to its initial content is removed; otherwise any
+// "modified" field in the file's front matter is updated to the current time
+func (app *App) PostEdit(filePath string, initialContent string, created bool, opened time.Time) error {
+	logger.Log.Info().Str("file_path", filePath).
+		Str("duration", time.Since(opened).Round(time.Second).String()).
+		Msg("file closed in editor")
+
+	// #nosec G304: Potential file inclusion via variable
+	// The file path is generated by the application (and was validated by the editor)
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file after editing: %w", err)
+	}
+
+	if string(content) == initialContent {
+		if created {
+			if err := os.Remove(filePath); err != nil {
+				return fmt.Errorf("failed to remove unchanged file: %w", err)
+			}
+			logger.Log.Info().Str("file_path", filePath).Msg("removed unchanged file")
+		}
+		return nil
+	}
+
+	now := time.Now()
+	if app.Location != nil {
+		now = now.In(app.Location)
+	}
+	updated, ok := frontmatter.SetField(string(content), "modified", now.Format(time.RFC3339))
+	if !ok {
+		return nil
+	}
+	if err := os.WriteFile(filePath, []byte(updated), 0600); err != nil {
+		return fmt.Errorf("failed to update modified time: %w", err)
+	}
+	logger.Log.Debug().Str("file_path", filePath).Msg("updated modified time in front matter")
+	return nil
+}
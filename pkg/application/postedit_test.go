@@ -0,0 +1,62 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostEdit(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	tempDir := t.TempDir()
+	initial := "---\nmodified: \n---\n# Standup\n"
+	tests := []struct {
+		name         string
+		content      string
+		created      bool
+		wantExists   bool
+		wantModified bool
+	}{
+		{
+			name:       "unchanged new file is removed",
+			content:    initial,
+			created:    true,
+			wantExists: false,
+		},
+		{
+			name:       "unchanged existing file is kept",
+			content:    initial,
+			created:    false,
+			wantExists: true,
+		},
+		{
+			name:         "edited file is kept",
+			content:      initial + "- fixed the build\n",
+			created:      true,
+			wantExists:   true,
+			wantModified: true,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tempDir, string(rune('a'+i))+".md")
+			assert.NoError(t, os.WriteFile(filePath, []byte(tt.content), 0600))
+
+			app := &App{Location: time.UTC}
+			err := app.PostEdit(filePath, initial, tt.created, time.Now().Add(-time.Minute))
+			assert.NoError(t, err)
+
+			content, err := os.ReadFile(filePath)
+			assert.Equal(t, tt.wantExists, err == nil)
+			assert.Equal(t, tt.wantModified, strings.HasPrefix(string(content), "---\nmodified: \"20"))
+		})
+	}
+}
@@ -3,20 +3,31 @@ package application
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/matthewchivers/journal/pkg/frontmatter"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/tags"
 	"github.com/matthewchivers/journal/pkg/templating"
 )
 
 // PreparePatternData prepares the pattern data for the application
+// The launch time is converted to the application's timezone (if set) before the data is prepared
 // This must be called before parsing any patterns
 func (app *App) PreparePatternData() error {
 	if app.LaunchTime.IsZero() {
 		return errors.New("launch time must be set before preparing pattern data")
 	}
+	if app.Location != nil {
+		app.LaunchTime = app.LaunchTime.In(app.Location)
+	}
 	templateModel, err := templating.PrepareTemplateData(app.LaunchTime)
 	if err != nil {
 		return fmt.Errorf("failed to prepare template data: %w", err)
 	}
+	templateModel.EntryID = app.EntryID
 
 	app.TemplateData = &templateModel
 	return nil
@@ -38,3 +49,103 @@ func (app *App) SetTopic(topic string) error {
 	}
 	return nil
 }
+
+// SetTags sets the tags for the entry: the tags in the entry configuration, plus the given tags
+func (app *App) SetTags(extraTags []string) error {
+	if app.TemplateData == nil {
+		return errors.New("pattern data must be initialised before setting tags")
+	}
+	entry, err := app.GetTargetEntry()
+	if err != nil {
+		return err
+	}
+	app.Tags = tags.Merge(entry.Tags, extraTags)
+	app.TemplateData.Tags = app.Tags
+	return nil
+}
+
+// SetTemplateName sets the name of the document template for the entry
+// If templateName is empty, the template named in the entry configuration is used
+func (app *App) SetTemplateName(templateName string) error {
+	if templateName != "" {
+		app.TemplateName = templateName
+		return nil
+	}
+	entry, err := app.GetTargetEntry()
+	if err != nil {
+		return err
+	}
+	app.TemplateName = entry.TemplateName
+	return nil
+}
+
+// GetDocumentContent renders the content of a new file for the entry: its front matter (see GetFrontMatter),
+// followed by its document template
+// If no template is set for the entry, only the front matter is rendered
+func (app *App) GetDocumentContent() (string, error) {
+	document := ""
+	if app.TemplateName != "" {
+		var err error
+		if document, err = app.renderTemplate(app.TemplateName); err != nil {
+			return "", err
+		}
+	}
+	if _, _, ok, _ := frontmatter.Split(document); ok {
+		logger.Log.Debug().Str("template", app.TemplateName).
+			Msg("document template has its own front matter - not adding configured front matter")
+		return document, nil
+	}
+	frontMatter, err := app.GetFrontMatter()
+	if err != nil {
+		return "", err
+	}
+	return frontMatter + document, nil
+}
+
+// GetSectionContent renders the section appended to an existing file by the append-section onExists policy:
+// a heading with the current time, followed by the entry's section template (if set)
+func (app *App) GetSectionContent() (string, error) {
+	entry, err := app.GetTargetEntry()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if app.Location != nil {
+		now = now.In(app.Location)
+	}
+	section := fmt.Sprintf("\n## %s\n\n", now.Format("15:04"))
+	if entry.SectionTemplateName == "" {
+		return section, nil
+	}
+	snippet, err := app.renderTemplate(entry.SectionTemplateName)
+	if err != nil {
+		return "", err
+	}
+	return section + snippet, nil
+}
+
+// renderTemplate renders the named template (within the templates directory) with the pattern data
+func (app *App) renderTemplate(templateName string) (string, error) {
+	if app.TemplateData == nil {
+		return "", errors.New("pattern data must be initialised before rendering the document template")
+	}
+	if app.TemplatesDirectory == "" {
+		return "", errors.New("templates directory must be set before rendering the document template")
+	}
+
+	templatePath := filepath.Join(app.TemplatesDirectory, templateName)
+	templateContent, err := os.ReadFile(templatePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template: %w", err)
+	}
+
+	document, err := app.TemplateData.ParseDocument(templateName, string(templateContent))
+	if err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	logger.Log.Debug().Str("template_path", templatePath).
+		Int("document_length", len(document)).
+		Msg("document template rendered")
+	return document, nil
+}
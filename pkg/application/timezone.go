@@ -0,0 +1,42 @@
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+)
+
+// SetTimezone sets the timezone for the application, and converts the launch time into it
+// If tz is empty, the timezone is taken from the target entry (if set), then the user settings,
+// falling back to the local timezone of the machine
+func (app *App) SetTimezone(tz string) error {
+	if tz != "" {
+		app.timezoneOverride = tz
+	}
+	app.Timezone = app.timezoneOverride
+	if app.Timezone == "" && app.targetEntry != nil {
+		app.Timezone = app.targetEntry.Timezone
+	}
+	if app.Timezone == "" && app.Config != nil {
+		app.Timezone = app.Config.UserSettings.Timezone
+	}
+
+	location := time.Local
+	if app.Timezone != "" {
+		loc, err := time.LoadLocation(app.Timezone)
+		if err != nil {
+			return fmt.Errorf("failed to load timezone %q: %w", app.Timezone, err)
+		}
+		location = loc
+	}
+	app.Location = location
+	if !app.LaunchTime.IsZero() {
+		app.LaunchTime = app.LaunchTime.In(location)
+	}
+
+	logger.Log.Debug().Str("timezone", location.String()).
+		Str("launch_time", app.LaunchTime.String()).
+		Msg("timezone set")
+	return nil
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTimezone(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	cfg := &config.Config{
+		UserSettings: config.UserSettings{Timezone: "Europe/London"},
+		Entries: []config.Entry{
+			{ID: "standup", Timezone: "Asia/Singapore"},
+			{ID: "note"},
+		},
+	}
+	launchTime := time.Date(2024, time.August, 2, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		flag     string
+		entryID  string
+		want     string
+		wantDay  int
+		wantHour int
+		wantErr  bool
+	}{
+		{name: "flag overrides entry", flag: "Pacific/Auckland", entryID: "standup", want: "Pacific/Auckland", wantDay: 3, wantHour: 11},
+		{name: "entry overrides user settings", entryID: "standup", want: "Asia/Singapore", wantDay: 3, wantHour: 7},
+		{name: "user settings", entryID: "note", want: "Europe/London", wantDay: 3, wantHour: 0},
+		{name: "unknown timezone", flag: "Mars/Olympus_Mons", entryID: "note", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{Config: cfg}
+			app.SetLaunchTime(launchTime)
+			assert.NoError(t, app.SetEntryID(tt.entryID))
+			err := app.SetTimezone(tt.flag)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, app.Location.String())
+			assert.True(t, app.LaunchTime.Equal(launchTime), "the launch time should be the same instant")
+			assert.Equal(t, tt.wantDay, app.LaunchTime.Day())
+			assert.Equal(t, tt.wantHour, app.LaunchTime.Hour())
+		})
+	}
+}
+
+func TestPreparePatternDataTimezone(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	app := &App{Config: &config.Config{Entries: []config.Entry{{ID: "standup"}}}}
+	app.SetLaunchTime(time.Date(2024, time.August, 2, 23, 30, 0, 0, time.UTC))
+	assert.NoError(t, app.SetEntryID("standup"))
+	assert.NoError(t, app.SetTimezone("Pacific/Auckland"))
+	assert.NoError(t, app.PreparePatternData())
+
+	// 23:30 UTC on Friday is already Saturday in Auckland
+	assert.Equal(t, "3", app.TemplateData.Day.Num)
+	assert.Equal(t, "Saturday", app.TemplateData.Day.Name)
+}
+
+func TestDeriveEntryTimezone(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	assert.NoError(t, err)
+	app := &App{
+		Config: &config.Config{
+			Paths:         config.Paths{BaseDirectory: t.TempDir()},
+			FileExtension: "md",
+			Entries: []config.Entry{
+				{
+					ID:               "standup",
+					Timezone:         "Pacific/Auckland",
+					DirectoryPattern: "{{.EntryID}}",
+					FileNamePattern:  "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+				},
+			},
+		},
+		TemplatesDirectory: t.TempDir(),
+	}
+
+	// The entry is dated by the wall clock date and time it was derived for, in the entry's timezone
+	// (e.g. a scheduled date), rather than by converting the instant into it
+	entryTime := time.Date(2024, time.August, 2, 23, 30, 0, 0, time.UTC)
+	derived, err := app.DeriveEntry("standup", entryTime)
+	assert.NoError(t, err)
+	assert.Equal(t, auckland, derived.Location)
+	assert.Equal(t, time.Date(2024, time.August, 2, 23, 30, 0, 0, auckland), derived.LaunchTime)
+	assert.Equal(t, "2024-08-02.md", derived.FileName)
+
+	// Converted into the entry's timezone, the same instant is the next day
+	app.SetLaunchTime(entryTime)
+	assert.NoError(t, app.SetEntryID("standup"))
+	assert.NoError(t, app.SetTimezone(""))
+	assert.NoError(t, app.PreparePatternData())
+	assert.Equal(t, "3", app.TemplateData.Day.Num)
+}
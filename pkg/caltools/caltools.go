@@ -30,6 +30,16 @@ func WeekCommencing(t time.Time) time.Time {
 	return weekCommencing
 }
 
+// ISOWeekday returns the ISO number of the weekday (1 = Monday ... 7 = Sunday)
+func ISOWeekday(weekday time.Weekday) int {
+	return (int(weekday)+6)%7 + 1
+}
+
+// StartOfDay returns midnight at the start of the given date
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
 // OrdinalSuffix returns the ordinal suffix for the given day of the month.
 // e.g. 1st, 2nd, 3rd, 4th
 func OrdinalSuffix(day int) string {
@@ -166,6 +166,18 @@ func TestOrdinalSuffix(t *testing.T) {
 	}
 }
 
+func TestISOWeekday(t *testing.T) {
+	assert.Equal(t, 1, ISOWeekday(time.Monday))
+	assert.Equal(t, 5, ISOWeekday(time.Friday))
+	assert.Equal(t, 7, ISOWeekday(time.Sunday))
+}
+
+func TestStartOfDay(t *testing.T) {
+	loc := time.FixedZone("UTC+10", 10*60*60)
+	date := time.Date(2024, time.August, 2, 23, 30, 15, 0, loc)
+	assert.Equal(t, time.Date(2024, time.August, 2, 0, 0, 0, 0, loc), StartOfDay(date))
+}
+
 func TestDaysInYear(t *testing.T) {
 	type args struct {
 		t time.Time
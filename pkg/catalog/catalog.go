@@ -0,0 +1,220 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/encryption"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/templating"
+)
+
+// Record describes an existing journal entry file
+type Record struct {
+	// Path is the full path to the file
+	Path string `json:"path"`
+
+	// EntryID is the ID of the entry type the file belongs to
+	EntryID string `json:"entryId"`
+
+	// Date is the date of the entry, recovered from its path (zero if the patterns do not contain a full date)
+	Date time.Time `json:"date"`
+
+	// Topic is the topic of the entry, recovered from its path (if the patterns contain one)
+	Topic string `json:"topic,omitempty"`
+
+	// FileExtension is the file extension of the entry, recovered from its path (if the patterns contain one)
+	FileExtension string `json:"fileExtension,omitempty"`
+
+	// Tags are the tags of the entry, read from its content (only set for records read from the index)
+	Tags []string `json:"tags,omitempty"`
+
+	// Encrypted is true if the file is encrypted (its content can only be read with the encryption key)
+	Encrypted bool `json:"encrypted,omitempty"`
+}
+
+// ReadRecord reads the content of a record's file
+// Returns false if the file has been removed since the record was found (e.g. the index is out of date)
+func ReadRecord(record Record) (string, bool, error) {
+	// #nosec G304: Potential file inclusion via variable
+	// The path was found by walking the configured base directories
+	content, err := os.ReadFile(record.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s: %w", record.Path, err)
+	}
+	return string(content), true, nil
+}
+
+// entryMatcher matches files in a base directory against the patterns of an entry type
+type entryMatcher struct {
+	// entryID is the ID of the entry type
+	entryID string
+
+	// baseDirectory is the directory the entry's patterns are relative to
+	baseDirectory string
+
+	// matcher matches paths (relative to the base directory) against the entry's patterns
+	matcher *templating.Matcher
+}
+
+// Scan walks the base directories of the configured entries, and returns a record for every file
+// that could have been produced by an entry's directory and file name patterns
+// Records are sorted by date, then path; files matching more than one entry type belong to the first
+// Dates recovered from paths are in the given location
+func Scan(cfg *config.Config, loc *time.Location) ([]Record, error) {
+	matchers, err := newEntryMatchers(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []Record{}
+	seen := map[string]bool{}
+	for _, baseDirectory := range baseDirectories(matchers) {
+		found, err := scanDirectory(baseDirectory, matchers, loc)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range found {
+			if !seen[record.Path] {
+				seen[record.Path] = true
+				records = append(records, record)
+			}
+		}
+	}
+
+	SortRecords(records)
+	return records, nil
+}
+
+// MatchPath returns the record for a single file, if it could have been produced by an entry's patterns
+// (the same record Scan would return for the file)
+func MatchPath(cfg *config.Config, loc *time.Location, filePath string) (Record, bool, error) {
+	matchers, err := newEntryMatchers(cfg)
+	if err != nil {
+		return Record{}, false, err
+	}
+	filePath = filepath.Clean(filePath)
+	for _, baseDirectory := range baseDirectories(matchers) {
+		if !strings.HasPrefix(filePath, baseDirectory+string(filepath.Separator)) {
+			continue
+		}
+		if record, ok := matchFile(baseDirectory, filePath, matchers, loc); ok {
+			return record, true, nil
+		}
+	}
+	return Record{}, false, nil
+}
+
+// SortRecords sorts records by date, then path
+func SortRecords(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if !records[i].Date.Equal(records[j].Date) {
+			return records[i].Date.Before(records[j].Date)
+		}
+		return records[i].Path < records[j].Path
+	})
+}
+
+// newEntryMatchers creates a matcher for each configured entry type
+func newEntryMatchers(cfg *config.Config) ([]entryMatcher, error) {
+	matchers := []entryMatcher{}
+	for _, entry := range cfg.Entries {
+		baseDirectory := cfg.Paths.BaseDirectory
+		if entry.BaseDirectory != "" {
+			baseDirectory = entry.BaseDirectory
+		}
+		pattern := path.Join(entry.DirectoryPattern, entry.FileNamePattern)
+		matcher, err := templating.NewMatcher(pattern, map[string]string{"EntryID": entry.ID})
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, entryMatcher{
+			entryID:       entry.ID,
+			baseDirectory: filepath.Clean(baseDirectory),
+			matcher:       matcher,
+		})
+	}
+	return matchers, nil
+}
+
+// baseDirectories returns the distinct base directories of the matchers
+func baseDirectories(matchers []entryMatcher) []string {
+	directories := []string{}
+	seen := map[string]bool{}
+	for _, m := range matchers {
+		if !seen[m.baseDirectory] {
+			seen[m.baseDirectory] = true
+			directories = append(directories, m.baseDirectory)
+		}
+	}
+	return directories
+}
+
+// scanDirectory walks a base directory, matching every file against the matchers for that directory
+// Hidden files and directories (e.g. .git) are skipped
+func scanDirectory(baseDirectory string, matchers []entryMatcher, loc *time.Location) ([]Record, error) {
+	records := []Record{}
+	err := filepath.WalkDir(baseDirectory, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if filePath == baseDirectory && errors.Is(err, fs.ErrNotExist) {
+				logger.Log.Debug().Str("base_dir", baseDirectory).Msg("base directory does not exist")
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && filePath != baseDirectory {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if record, ok := matchFile(baseDirectory, filePath, matchers, loc); ok {
+			records = append(records, record)
+		}
+		return nil
+	})
+	return records, err
+}
+
+// matchFile matches a file against the matchers for its base directory
+// Encrypted files are matched by their name without the encryption extension
+func matchFile(baseDirectory string, filePath string, matchers []entryMatcher, loc *time.Location) (Record, bool) {
+	relPath, err := filepath.Rel(baseDirectory, filePath)
+	if err != nil {
+		return Record{}, false
+	}
+	encrypted := encryption.IsEncrypted(relPath)
+	relPath = strings.TrimSuffix(filepath.ToSlash(relPath), encryption.Extension)
+	for _, m := range matchers {
+		if m.baseDirectory != baseDirectory {
+			continue
+		}
+		match, ok := m.matcher.Match(relPath, loc)
+		if !ok {
+			continue
+		}
+		return Record{
+			Path:          filePath,
+			EntryID:       m.entryID,
+			Date:          match.Date,
+			Topic:         match.Topic,
+			FileExtension: match.FileExtension,
+			Encrypted:     encrypted,
+		}, true
+	}
+	return Record{}, false
+}
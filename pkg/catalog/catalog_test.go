@@ -0,0 +1,178 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	baseDir := t.TempDir()
+	files := []string{
+		"standups/wc-29-07-24/Fri-2nd-Aug-2024.md",
+		"standups/wc-29-07-24/Mon-29th-Jul-2024.md",
+		"meetings/planning/2024-08-01.md",
+		"meetings/one-to-one/2024-08-01.md.enc",
+		"meetings/notes.txt",
+		".git/standups/wc-29-07-24/Thu-1st-Aug-2024.md",
+	}
+	for _, file := range files {
+		filePath := filepath.Join(baseDir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.NoError(t, os.WriteFile(filePath, []byte{}, 0644))
+	}
+
+	cfg := &config.Config{
+		Paths: config.Paths{BaseDirectory: baseDir},
+		Entries: []config.Entry{
+			{
+				ID:               "standup",
+				DirectoryPattern: "{{.EntryID}}s/wc-{{.WkCom.Day.Pad}}-{{.WkCom.Month.Pad}}-{{.WkCom.Year.Short}}",
+				FileNamePattern:  "{{.Day.Short}}-{{.Day.Ord}}-{{.Month.Short}}-{{.Year.Num}}.{{.FileExtension}}",
+			},
+			{
+				ID:               "meeting",
+				DirectoryPattern: "{{.EntryID}}s/{{.Topic}}",
+				FileNamePattern:  "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.{{.FileExtension}}",
+			},
+		},
+	}
+
+	records, err := Scan(cfg, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{
+		{
+			Path:          filepath.Join(baseDir, "standups/wc-29-07-24/Mon-29th-Jul-2024.md"),
+			EntryID:       "standup",
+			Date:          time.Date(2024, time.July, 29, 0, 0, 0, 0, time.UTC),
+			FileExtension: "md",
+		},
+		{
+			Path:          filepath.Join(baseDir, "meetings/one-to-one/2024-08-01.md.enc"),
+			EntryID:       "meeting",
+			Date:          time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+			Topic:         "one-to-one",
+			FileExtension: "md",
+			Encrypted:     true,
+		},
+		{
+			Path:          filepath.Join(baseDir, "meetings/planning/2024-08-01.md"),
+			EntryID:       "meeting",
+			Date:          time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+			Topic:         "planning",
+			FileExtension: "md",
+		},
+		{
+			Path:          filepath.Join(baseDir, "standups/wc-29-07-24/Fri-2nd-Aug-2024.md"),
+			EntryID:       "standup",
+			Date:          time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			FileExtension: "md",
+		},
+	}, records)
+}
+
+func TestScanMissingBaseDirectory(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	cfg := &config.Config{
+		Paths: config.Paths{BaseDirectory: filepath.Join(t.TempDir(), "missing")},
+		Entries: []config.Entry{
+			{ID: "note", FileNamePattern: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md"},
+		},
+	}
+	records, err := Scan(cfg, time.UTC)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestMatchPath(t *testing.T) {
+	baseDir := "/journal"
+	cfg := &config.Config{
+		Paths: config.Paths{BaseDirectory: baseDir},
+		Entries: []config.Entry{
+			{
+				ID:               "meeting",
+				DirectoryPattern: "{{.EntryID}}s/{{.Topic}}",
+				FileNamePattern:  "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.{{.FileExtension}}",
+			},
+		},
+	}
+
+	record, ok, err := MatchPath(cfg, time.UTC, "/journal/meetings/planning/2024-08-01.md")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Record{
+		Path:          "/journal/meetings/planning/2024-08-01.md",
+		EntryID:       "meeting",
+		Date:          time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+		Topic:         "planning",
+		FileExtension: "md",
+	}, record)
+
+	_, ok, err = MatchPath(cfg, time.UTC, "/journal/meetings/planning/notes.md")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = MatchPath(cfg, time.UTC, "/elsewhere/meetings/planning/2024-08-01.md")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFilterMatches(t *testing.T) {
+	record := Record{
+		EntryID: "meeting",
+		Date:    time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+		Topic:   "Project Alpha",
+		Tags:    []string{"client-x", "planning"},
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "empty filter", filter: Filter{}, want: true},
+		{name: "entry ID", filter: Filter{EntryID: "meeting"}, want: true},
+		{name: "other entry ID", filter: Filter{EntryID: "standup"}, want: false},
+		{name: "topic substring", filter: Filter{Topic: "alpha"}, want: true},
+		{name: "other topic", filter: Filter{Topic: "beta"}, want: false},
+		{name: "since same day", filter: Filter{Since: time.Date(2024, time.August, 2, 15, 0, 0, 0, time.UTC)}, want: true},
+		{name: "since later", filter: Filter{Since: time.Date(2024, time.August, 3, 0, 0, 0, 0, time.UTC)}, want: false},
+		{name: "until same day", filter: Filter{Until: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)}, want: true},
+		{name: "tags", filter: Filter{Tags: []string{"Client X", "#planning"}}, want: true},
+		{name: "missing tag", filter: Filter{Tags: []string{"planning", "retro"}}, want: false},
+		{name: "until earlier", filter: Filter{Until: time.Date(2024, time.August, 1, 23, 0, 0, 0, time.UTC)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(record))
+		})
+	}
+}
+
+func TestReadRecord(t *testing.T) {
+	dir := t.TempDir()
+	record := Record{Path: filepath.Join(dir, "2024-08-01.md")}
+	assert.NoError(t, os.WriteFile(record.Path, []byte("# Planning\n"), 0600))
+
+	content, ok, err := ReadRecord(record)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "# Planning\n", content)
+
+	_, ok, err = ReadRecord(Record{Path: filepath.Join(dir, "removed.md")})
+	assert.NoError(t, err)
+	assert.False(t, ok, "files removed since they were found are reported as missing")
+
+	_, _, err = ReadRecord(Record{Path: dir})
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package catalogtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+)
+
+// File is the file of a record, written by WriteFiles
+type File struct {
+	// Name is the path of the file, relative to the directory it is written to
+	Name string
+
+	// Record is the record of the file (its path is set by WriteFiles)
+	Record catalog.Record
+
+	// Content is the content of the file
+	Content string
+}
+
+// WriteFiles writes the files to dir, and returns their records (in the same order) with their paths set
+func WriteFiles(t testing.TB, dir string, files ...File) []catalog.Record {
+	t.Helper()
+	records := make([]catalog.Record, 0, len(files))
+	for _, file := range files {
+		record := file.Record
+		record.Path = filepath.Join(dir, file.Name)
+		if err := os.MkdirAll(filepath.Dir(record.Path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(record.Path, []byte(file.Content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
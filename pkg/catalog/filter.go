@@ -0,0 +1,60 @@
+package catalog
+
+import (
+	"strings"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/caltools"
+	"github.com/matthewchivers/journal/pkg/tags"
+)
+
+// Filter selects records by entry type, date, topic and tags
+// Zero values match every record
+type Filter struct {
+	// EntryID selects records of the given entry type
+	EntryID string
+
+	// Since selects records dated on or after the given date
+	Since time.Time
+
+	// Until selects records dated on or before the given date
+	Until time.Time
+
+	// Topic selects records whose topic contains the given text (case insensitive)
+	Topic string
+
+	// Tags selects records with all of the given tags
+	Tags []string
+}
+
+// Matches reports whether the record is selected by the filter
+// Records without a date are excluded when filtering by date
+func (f Filter) Matches(record Record) bool {
+	if f.EntryID != "" && !strings.EqualFold(f.EntryID, record.EntryID) {
+		return false
+	}
+	if f.Topic != "" && !strings.Contains(strings.ToLower(record.Topic), strings.ToLower(f.Topic)) {
+		return false
+	}
+	if !tags.ContainsAll(record.Tags, f.Tags) {
+		return false
+	}
+	if !f.Since.IsZero() && (record.Date.IsZero() || record.Date.Before(caltools.StartOfDay(f.Since))) {
+		return false
+	}
+	if !f.Until.IsZero() && (record.Date.IsZero() || !record.Date.Before(caltools.StartOfDay(f.Until).AddDate(0, 0, 1))) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the records selected by the filter
+func (f Filter) Apply(records []Record) []Record {
+	selected := []Record{}
+	for _, record := range records {
+		if f.Matches(record) {
+			selected = append(selected, record)
+		}
+	}
+	return selected
+}
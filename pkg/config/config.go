@@ -11,9 +11,19 @@ type Config struct {
 	// DefaultEntry: specify the entry id of the desired default entry
 	DefaultEntry string `yaml:"defaultEntry"`
 
-	// Editor is the editor to use when opening files
+	// Editor is the editor to use when opening files: the name of a built-in editor (vscode, vim, nvim,
+	// nano, emacs, helix, micro, sublime) or a command template (e.g. "gvim --remote-tab {{.Path}}")
+	// Falls back to $VISUAL, then $EDITOR, if not set
 	Editor string `yaml:"editor,omitempty"`
 
+	// EditorWait makes the CLI wait for the editor to close the file before exiting, so that post-edit
+	// steps can run (e.g. removing files that were left unchanged)
+	EditorWait bool `yaml:"editorWait,omitempty"`
+
+	// OnExists is what to do when the file for a new entry already exists
+	// (open, error, append-section, suffix or overwrite - defaults to open; can be overridden per entry)
+	OnExists string `yaml:"onExists,omitempty"`
+
 	// FileExtension is the file extension to use when creating a new entry (can be overridden per entry)
 	FileExtension string `yaml:"fileExtension,omitempty"`
 
@@ -23,6 +33,15 @@ type Config struct {
 	// Paths contains the paths to directories used by the application
 	Paths Paths `yaml:"paths"`
 
+	// Digest contains the configuration for digests of the entries of a period
+	Digest Digest `yaml:"digest,omitempty"`
+
+	// Hooks are the commands run when creating and editing entries (before the hooks of the entry)
+	Hooks Hooks `yaml:"hooks,omitempty"`
+
+	// Git contains the configuration for committing entries to git and synchronising them with a remote
+	Git Git `yaml:"git,omitempty"`
+
 	// UserSettings contains user-specific settings
 	UserSettings UserSettings `yaml:"userSettings,omitempty"`
 }
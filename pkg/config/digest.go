@@ -0,0 +1,15 @@
+package config
+
+// DefaultDigestEntryID is the ID of the entry digests are saved as, if none is configured
+const DefaultDigestEntryID = "digest"
+
+// Digest contains the configuration for digests (documents combining the entries of a week, month or year)
+type Digest struct {
+	// EntryID is the ID of the entry whose directory and file name patterns digests are saved with
+	// (default: "digest"); the patterns are populated with the date of the start of the period
+	EntryID string `yaml:"entryId,omitempty"`
+
+	// TemplateName is the name of the template (within the templates directory) used to render digests
+	// (if not specified, a built-in template is used)
+	TemplateName string `yaml:"templateName,omitempty"`
+}
@@ -1,5 +1,9 @@
 package config
 
+import (
+	yaml "gopkg.in/yaml.v2"
+)
+
 // Entry contains the configuration for a entry type
 type Entry struct {
 	// ID is the identifier for the entry
@@ -20,14 +24,41 @@ type Entry struct {
 	// BaseDirectory
 	BaseDirectory string `yaml:"baseDirectory,omitempty"`
 
-	// TemplateName is the name of the template to use when creating a new entry
-	// (if not specified, the default template will be used)
+	// TemplateName is the name of the template (within the templates directory) to use when creating a new entry
+	// (if not specified, the new file is created empty)
 	TemplateName string `yaml:"templateName,omitempty"`
 
+	// FrontMatter are the fields of the YAML front matter added to the top of new Markdown files, in order
+	// Values are patterns (e.g. date: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}"), and lists of patterns are allowed
+	FrontMatter yaml.MapSlice `yaml:"frontMatter,omitempty"`
+
+	// SectionTemplateName is the name of the template (within the templates directory) appended to an existing
+	// file by the append-section onExists policy (if not specified, only the timestamp heading is appended)
+	SectionTemplateName string `yaml:"sectionTemplateName,omitempty"`
+
+	// OnExists is what to do when the file for a new entry already exists (overrides the default onExists)
+	OnExists string `yaml:"onExists,omitempty"`
+
 	// Topic is a name to be used for templating (e.g. a meeting about a certain topic)
 	// Expect this to be primarily set using cli params, but can be set in the config file
 	Topic string `yaml:"topic,omitempty"`
 
-	// Editor is the editor to use when opening files
+	// Tags are the tags given to every new entry (in addition to any given on the command line)
+	Tags []string `yaml:"tags,omitempty"`
+
+	// Editor is the editor to use when opening files (overrides the default editor)
 	Editor string `yaml:"editor,omitempty"`
+
+	// EditorWait overrides the default editorWait setting for the entry (if set)
+	EditorWait *bool `yaml:"editorWait,omitempty"`
+
+	// Hooks are the commands run when creating and editing the entry (after the default hooks)
+	Hooks Hooks `yaml:"hooks,omitempty"`
+
+	// Encrypt stores the entry's files encrypted (with the key at paths.keyPath), adding the ".enc" extension
+	// Encrypted files are decrypted to a private temporary file for editing, and their content is not indexed
+	Encrypt bool `yaml:"encrypt,omitempty"`
+
+	// Timezone is the IANA timezone used to date the entry (e.g. "Asia/Singapore"), overriding the user settings
+	Timezone string `yaml:"timezone,omitempty"`
 }
@@ -0,0 +1,23 @@
+package config
+
+// DefaultCommitMessage is the pattern commit messages are rendered from, if none is configured
+const DefaultCommitMessage = "Update {{.EntryID}} entry for {{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}{{if .Topic}} ({{.Topic}}){{end}}"
+
+// DefaultGitRemote is the remote synchronised with, if none is configured
+const DefaultGitRemote = "origin"
+
+// Git contains the configuration for keeping the journal in a git repository
+type Git struct {
+	// Enabled commits new and changed entries to the git repository containing the base directory
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// CommitMessage is the pattern commit messages are rendered from (using the entry's template fields)
+	CommitMessage string `yaml:"commitMessage,omitempty"`
+
+	// Remote is the remote 'journal sync' pulls from and pushes to: the name of a remote, a URL, or
+	// the path to a (bare) repository (default: origin)
+	Remote string `yaml:"remote,omitempty"`
+
+	// Branch is the branch 'journal sync' pulls and pushes (default: the current branch)
+	Branch string `yaml:"branch,omitempty"`
+}
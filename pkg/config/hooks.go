@@ -0,0 +1,14 @@
+package config
+
+// Hooks are the commands run at points in the creation of an entry (each run with the shell)
+// Commands are given a JSON description of the entry on stdin, and in JOURNAL_* environment variables
+type Hooks struct {
+	// PreCreate commands run before the entry's file is created; if one fails, the entry is not created
+	PreCreate []string `yaml:"preCreate,omitempty"`
+
+	// PostCreate commands run after the entry's file is created (or, depending on onExists, found to exist)
+	PostCreate []string `yaml:"postCreate,omitempty"`
+
+	// PostEdit commands run after the editor has closed the entry's file (when waiting for the editor)
+	PostEdit []string `yaml:"postEdit,omitempty"`
+}
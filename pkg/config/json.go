@@ -0,0 +1,55 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// JSON returns the configuration as JSON, with the same field names as the YAML configuration file
+// (e.g. for passing the configuration to plugins)
+func (cfg *Config) JSON() ([]byte, error) {
+	yamlData, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var fields interface{}
+	if err := yaml.Unmarshal(yamlData, &fields); err != nil {
+		return nil, err
+	}
+	fields, err = jsonValue(fields)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// jsonValue converts a value decoded from YAML into one that can be encoded as JSON
+// (YAML mappings are decoded with keys of any type, but JSON objects need string keys)
+func jsonValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		object := map[string]interface{}{}
+		for key, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			object[fmt.Sprint(key)] = converted
+		}
+		return object, nil
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = converted
+		}
+		return array, nil
+	default:
+		return v, nil
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/matthewchivers/journal/pkg/logger"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -124,3 +125,35 @@ paths:
 		})
 	}
 }
+
+func TestJSON(t *testing.T) {
+	wait := true
+	cfg := &Config{
+		DefaultEntry: "meeting",
+		Entries: []Entry{
+			{
+				ID:              "meeting",
+				FileNamePattern: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+				EditorWait:      &wait,
+				FrontMatter:     yaml.MapSlice{{Key: "date", Value: "{{.Year.Num}}"}, {Key: "tags", Value: []interface{}{"work"}}},
+				Schedule:        Schedule{Frequency: "weekly", Days: []int{1}},
+			},
+		},
+		Paths: Paths{BaseDirectory: "/journal"},
+		Git:   Git{Enabled: true},
+	}
+	got, err := cfg.JSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"defaultEntry": "meeting",
+		"entries": [{
+			"id": "meeting",
+			"fileNamePattern": "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+			"editorWait": true,
+			"frontMatter": {"date": "{{.Year.Num}}", "tags": ["work"]},
+			"schedule": {"frequency": "weekly", "days": [1]}
+		}],
+		"paths": {"baseDirectory": "/journal"},
+		"git": {"enabled": true}
+	}`, string(got))
+}
@@ -0,0 +1,18 @@
+package config
+
+const (
+	// OnExistsOpen opens the existing file as it is (the default)
+	OnExistsOpen = "open"
+
+	// OnExistsError fails if the file already exists
+	OnExistsError = "error"
+
+	// OnExistsAppendSection appends a section (a timestamp heading and a rendered template snippet) to the existing file
+	OnExistsAppendSection = "append-section"
+
+	// OnExistsSuffix creates a new file with a numbered suffix (e.g. name-2.md, name-3.md)
+	OnExistsSuffix = "suffix"
+
+	// OnExistsOverwrite backs up the existing file (e.g. name.md.bak), then replaces it
+	OnExistsOverwrite = "overwrite"
+)
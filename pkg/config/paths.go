@@ -5,6 +5,12 @@ type Paths struct {
 	// TemplatesDirectory is the path to the templates directory (default: ~/.journal/templates)
 	TemplatesDirectory string `yaml:"templatesDirectory,omitempty"`
 
+	// IndexPath is the path to the index of entries (default: ~/.journal/index.json)
+	IndexPath string `yaml:"indexPath,omitempty"`
+
+	// KeyPath is the path to the key encrypted entries are encrypted with (default: ~/.journal/journal.key)
+	KeyPath string `yaml:"keyPath,omitempty"`
+
 	// BaseDirectory is the base directory for entries (default is: ~/journal)
 	BaseDirectory string `yaml:"baseDirectory"`
 }
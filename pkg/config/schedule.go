@@ -1,5 +1,19 @@
 package config
 
+const (
+	// FrequencyDaily schedules an entry every day (or every Interval days)
+	FrequencyDaily = "daily"
+
+	// FrequencyWeekly schedules an entry every week (or every Interval weeks)
+	FrequencyWeekly = "weekly"
+
+	// FrequencyMonthly schedules an entry every month (or every Interval months)
+	FrequencyMonthly = "monthly"
+
+	// FrequencyYearly schedules an entry every year (or every Interval years)
+	FrequencyYearly = "yearly"
+)
+
 // Schedule contains the schedule for a file type
 type Schedule struct {
 	// Frequency is the frequency of the schedule (daily, weekly, monthly, yearly)
@@ -19,4 +33,8 @@ type Schedule struct {
 
 	// Months are the months of the year to create entries (e.g. 1, 3, 5 => January, March, May)
 	Months []int `yaml:"months,omitempty"`
+
+	// StartDate is the date (YYYY-MM-DD) the schedule starts from, and the anchor for intervals
+	// (e.g. Interval: 2, Frequency: weekly => every other week, counting from the week of StartDate)
+	StartDate string `yaml:"startDate,omitempty"`
 }
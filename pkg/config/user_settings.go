@@ -2,6 +2,7 @@ package config
 
 // UserSettings contains user-specific settings
 type UserSettings struct {
-	// Timezone is the timezone to use for the application
+	// Timezone is the IANA timezone to use for the application (e.g. "Europe/London")
+	// Dates are calculated in the local timezone of the machine if not set
 	Timezone string `yaml:"timezone,omitempty"`
 }
@@ -2,6 +2,12 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"path"
+	"text/template"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/templating"
 )
 
 // Validate checks that the provided configuration is valid
@@ -12,6 +18,18 @@ func (cfg *Config) Validate() error {
 	if err := validateEntries(cfg.Entries, cfg.FileExtension); err != nil {
 		return err
 	}
+	if err := validateTimezone(cfg.UserSettings.Timezone); err != nil {
+		return err
+	}
+	if err := ValidateOnExists(cfg.OnExists); err != nil {
+		return err
+	}
+	if err := validateDigest(cfg); err != nil {
+		return err
+	}
+	if err := validateGit(cfg.Git); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -35,7 +53,105 @@ func validateEntries(entries []Entry, fileExt string) error {
 		if entry.FileExtension == "" && fileExt == "" {
 			return errors.New("file extension not set")
 		}
+		if err := validateSchedule(entry.Schedule); err != nil {
+			return fmt.Errorf("invalid schedule for entry %q: %w", entry.ID, err)
+		}
+		if err := validateTimezone(entry.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone for entry %q: %w", entry.ID, err)
+		}
+		if err := ValidateOnExists(entry.OnExists); err != nil {
+			return fmt.Errorf("invalid onExists for entry %q: %w", entry.ID, err)
+		}
+		// Entries are found (e.g. by 'list' and the index) by matching paths against the patterns, so patterns
+		// that cannot be matched would break every command that reads the index
+		pattern := path.Join(entry.DirectoryPattern, entry.FileNamePattern)
+		if _, err := templating.NewMatcher(pattern, map[string]string{"EntryID": entry.ID}); err != nil {
+			return fmt.Errorf("invalid patterns for entry %q: %w", entry.ID, err)
+		}
 	}
 
 	return nil
 }
+
+// validateSchedule checks that the schedule for an entry is valid
+func validateSchedule(schedule Schedule) error {
+	switch schedule.Frequency {
+	case "", FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		return fmt.Errorf("unknown frequency: %s", schedule.Frequency)
+	}
+	if schedule.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	if err := validateRange("days", schedule.Days, 1, 7); err != nil {
+		return err
+	}
+	if err := validateRange("dates", schedule.Dates, 1, 31); err != nil {
+		return err
+	}
+	if err := validateRange("weeks", schedule.Weeks, 1, 6); err != nil {
+		return err
+	}
+	if err := validateRange("months", schedule.Months, 1, 12); err != nil {
+		return err
+	}
+	if schedule.StartDate != "" {
+		if _, err := time.Parse(time.DateOnly, schedule.StartDate); err != nil {
+			return fmt.Errorf("invalid start date: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateRange checks that all values are within the (inclusive) range lower-upper
+func validateRange(name string, values []int, lower, upper int) error {
+	for _, value := range values {
+		if value < lower || value > upper {
+			return fmt.Errorf("%s must be between %d and %d: %d", name, lower, upper, value)
+		}
+	}
+	return nil
+}
+
+// validateTimezone checks that the timezone (if set) is a known IANA timezone
+func validateTimezone(tz string) error {
+	if tz == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("unknown timezone %q: %w", tz, err)
+	}
+	return nil
+}
+
+// ValidateOnExists checks that the onExists policy (if set) is known
+func ValidateOnExists(policy string) error {
+	switch policy {
+	case "", OnExistsOpen, OnExistsError, OnExistsAppendSection, OnExistsSuffix, OnExistsOverwrite:
+		return nil
+	default:
+		return fmt.Errorf("unknown onExists policy: %s", policy)
+	}
+}
+
+// validateDigest checks that the digest entry (if set) is a configured entry
+func validateDigest(cfg *Config) error {
+	if cfg.Digest.EntryID == "" {
+		return nil
+	}
+	if _, err := cfg.FetchEntryByID(cfg.Digest.EntryID); err != nil {
+		return fmt.Errorf("invalid digest entry: %w", err)
+	}
+	return nil
+}
+
+// validateGit checks that the commit message pattern (if set) is a valid template
+func validateGit(git Git) error {
+	if git.CommitMessage == "" {
+		return nil
+	}
+	if _, err := template.New("commitMessage").Funcs(templating.FuncMap()).Parse(git.CommitMessage); err != nil {
+		return fmt.Errorf("invalid git commit message: %w", err)
+	}
+	return nil
+}
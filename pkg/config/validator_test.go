@@ -69,6 +69,302 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unknown schedule frequency",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+							Schedule: Schedule{
+								Frequency: "fortnightly",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "schedule day out of range",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+							Schedule: Schedule{
+								Frequency: "weekly",
+								Days:      []int{0},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid schedule start date",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+							Schedule: Schedule{
+								Frequency: "daily",
+								StartDate: "01/02/2024",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown user timezone",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+						},
+					},
+					UserSettings: UserSettings{
+						Timezone: "Europe/Atlantis",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown entry timezone",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+							Timezone:      "Asia/Nowhere",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "successful validation with timezones",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+							Timezone:      "Asia/Singapore",
+						},
+					},
+					UserSettings: UserSettings{
+						Timezone: "Europe/London",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown entry onExists policy",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+							OnExists:      "replace",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "successful validation with onExists policies",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					OnExists: "suffix",
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+							OnExists:      "append-section",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "patterns whose date cannot be recovered",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:               "foo",
+							FileExtension:    "md",
+							DirectoryPattern: "{{.EntryID}}s",
+							FileNamePattern:  "{{.Topic | slugify}}-{{.Time | format \"Jan 2\"}}.{{.FileExtension}}",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "successful validation with a formatted date pattern",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:               "foo",
+							FileExtension:    "md",
+							DirectoryPattern: "{{.EntryID}}s",
+							FileNamePattern:  "{{.Topic | slugify}}-{{.Time | format \"2006-01-02\"}}.{{.FileExtension}}",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown digest entry",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Digest: Digest{EntryID: "summary"},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "successful validation with digest",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Digest: Digest{EntryID: "summary", TemplateName: "digest.md"},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+						},
+						{
+							ID:            "summary",
+							FileExtension: "md",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid git commit message",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Git: Git{Enabled: true, CommitMessage: "{{.EntryID"},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "successful validation with git",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Git: Git{Enabled: true, CommitMessage: "{{.EntryID}} {{.Topic}}", Remote: "/srv/journal.git"},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "successful validation with schedule",
+			args: args{
+				cfg: Config{
+					Paths: Paths{
+						BaseDirectory: "/tmp",
+					},
+					Entries: []Entry{
+						{
+							ID:            "foo",
+							FileExtension: "md",
+							Schedule: Schedule{
+								Frequency: "monthly",
+								Interval:  2,
+								Days:      []int{1},
+								Weeks:     []int{1, 3},
+								StartDate: "2024-01-01",
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "successful validation",
 			args: args{
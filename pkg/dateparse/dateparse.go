@@ -0,0 +1,189 @@
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/caltools"
+)
+
+// dateLayouts are the absolute date formats accepted by Parse (parsed in the location of "now")
+var dateLayouts = []string{
+	time.DateOnly,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	time.RFC3339,
+}
+
+// weekdays maps the full and short names of the days of the week to their time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var (
+	// offsetPattern matches offsets such as "+3d", "-2w", "+1m" and "-1y"
+	offsetPattern = regexp.MustCompile(`^([+-]\d+)\s*([dwmy])$`)
+
+	// agoPattern matches phrases such as "3 days ago" and "in 2 weeks"
+	agoPattern = regexp.MustCompile(`^(?:(in)\s+)?(\d+)\s+(day|week|month|year)s?(?:\s+(ago))?$`)
+)
+
+// Parse resolves a date expression relative to now
+// Accepted expressions are:
+//   - absolute dates: "2024-08-02", "2024-08-02T09:30", "2024-08-02 09:30" or RFC3339
+//   - "today", "yesterday", "tomorrow"
+//   - weekdays: "friday" (the most recent, including today), "last friday", "next monday", "this wednesday"
+//   - relative periods: "last week", "next month", "last year"
+//   - offsets: "+3d", "-2w", "+1m", "-1y", "3 days ago", "in 2 weeks"
+//
+// Relative expressions keep the time of day from now; absolute dates without a time resolve to midnight
+func Parse(expression string, now time.Time) (time.Time, error) {
+	expression = strings.Join(strings.Fields(expression), " ")
+	if expression == "" {
+		return time.Time{}, errors.New("empty date expression")
+	}
+	for _, layout := range dateLayouts {
+		if date, err := time.ParseInLocation(layout, expression, now.Location()); err == nil {
+			return date, nil
+		}
+	}
+	expression = strings.ToLower(expression)
+	parsers := []func(string, time.Time) (time.Time, bool){
+		parseKeyword,
+		parseWeekday,
+		parseRelativePeriod,
+		parseOffset,
+		parseAgo,
+	}
+	for _, parse := range parsers {
+		if date, ok := parse(expression, now); ok {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date expression: %q", expression)
+}
+
+// parseKeyword resolves "today", "yesterday" and "tomorrow"
+func parseKeyword(expression string, now time.Time) (time.Time, bool) {
+	switch expression {
+	case "today", "now":
+		return now, true
+	case "yesterday":
+		return now.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), true
+	}
+	return time.Time{}, false
+}
+
+// parseWeekday resolves weekday names, optionally qualified with "last", "next" or "this"
+// - "friday": the most recent Friday (today, if today is a Friday)
+// - "last friday": the most recent Friday before today
+// - "next friday": the first Friday after today
+// - "this friday": the Friday of the current (Monday to Sunday) week
+func parseWeekday(expression string, now time.Time) (time.Time, bool) {
+	qualifier, name, found := strings.Cut(expression, " ")
+	if !found {
+		qualifier, name = "", expression
+	}
+	weekday, ok := weekdays[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	daysBack := (int(now.Weekday()) - int(weekday) + 7) % 7
+	switch qualifier {
+	case "":
+		return now.AddDate(0, 0, -daysBack), true
+	case "last":
+		if daysBack == 0 {
+			daysBack = 7
+		}
+		return now.AddDate(0, 0, -daysBack), true
+	case "next":
+		daysForward := (int(weekday) - int(now.Weekday()) + 7) % 7
+		if daysForward == 0 {
+			daysForward = 7
+		}
+		return now.AddDate(0, 0, daysForward), true
+	case "this":
+		return now.AddDate(0, 0, caltools.ISOWeekday(weekday)-caltools.ISOWeekday(now.Weekday())), true
+	}
+	return time.Time{}, false
+}
+
+// parseRelativePeriod resolves "last week", "next month", "last year", etc.
+func parseRelativePeriod(expression string, now time.Time) (time.Time, bool) {
+	qualifier, period, found := strings.Cut(expression, " ")
+	if !found {
+		return time.Time{}, false
+	}
+	direction := 0
+	switch qualifier {
+	case "last":
+		direction = -1
+	case "next":
+		direction = 1
+	default:
+		return time.Time{}, false
+	}
+	return addPeriod(now, direction, period)
+}
+
+// parseOffset resolves offsets such as "+3d", "-2w", "+1m" and "-1y"
+func parseOffset(expression string, now time.Time) (time.Time, bool) {
+	matches := offsetPattern.FindStringSubmatch(expression)
+	if matches == nil {
+		return time.Time{}, false
+	}
+	amount, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	units := map[string]string{"d": "day", "w": "week", "m": "month", "y": "year"}
+	return addPeriod(now, amount, units[matches[2]])
+}
+
+// parseAgo resolves phrases such as "3 days ago" and "in 2 weeks"
+func parseAgo(expression string, now time.Time) (time.Time, bool) {
+	matches := agoPattern.FindStringSubmatch(expression)
+	if matches == nil {
+		return time.Time{}, false
+	}
+	inFuture, amountStr, period, inPast := matches[1] != "", matches[2], matches[3], matches[4] != ""
+	if inFuture == inPast {
+		return time.Time{}, false
+	}
+	amount, err := strconv.Atoi(amountStr)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if inPast {
+		amount = -amount
+	}
+	return addPeriod(now, amount, period)
+}
+
+// addPeriod adds amount periods (day, week, month or year) to the date
+func addPeriod(date time.Time, amount int, period string) (time.Time, bool) {
+	switch period {
+	case "day":
+		return date.AddDate(0, 0, amount), true
+	case "week":
+		return date.AddDate(0, 0, amount*7), true
+	case "month":
+		return date.AddDate(0, amount, 0), true
+	case "year":
+		return date.AddDate(amount, 0, 0), true
+	}
+	return time.Time{}, false
+}
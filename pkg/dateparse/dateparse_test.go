@@ -0,0 +1,147 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	// Wednesday 7th August 2024, 09:30
+	now := time.Date(2024, time.August, 7, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expression string
+		want       time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "iso date",
+			expression: "2024-06-28",
+			want:       time.Date(2024, time.June, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "iso date and time",
+			expression: "2024-06-28T14:15",
+			want:       time.Date(2024, time.June, 28, 14, 15, 0, 0, time.UTC),
+		},
+		{
+			name:       "today",
+			expression: "today",
+			want:       now,
+		},
+		{
+			name:       "yesterday",
+			expression: "Yesterday",
+			want:       time.Date(2024, time.August, 6, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "tomorrow",
+			expression: " tomorrow ",
+			want:       time.Date(2024, time.August, 8, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "weekday - earlier this week",
+			expression: "monday",
+			want:       time.Date(2024, time.August, 5, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "weekday - today",
+			expression: "wed",
+			want:       now,
+		},
+		{
+			name:       "last friday",
+			expression: "last friday",
+			want:       time.Date(2024, time.August, 2, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "last wednesday is a week ago",
+			expression: "last wednesday",
+			want:       time.Date(2024, time.July, 31, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "next monday",
+			expression: "next monday",
+			want:       time.Date(2024, time.August, 12, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "next wednesday is a week away",
+			expression: "next  wednesday",
+			want:       time.Date(2024, time.August, 14, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "this sunday",
+			expression: "this sunday",
+			want:       time.Date(2024, time.August, 11, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "last week",
+			expression: "last week",
+			want:       time.Date(2024, time.July, 31, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "next month",
+			expression: "next month",
+			want:       time.Date(2024, time.September, 7, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "plus days",
+			expression: "+3d",
+			want:       time.Date(2024, time.August, 10, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "minus weeks",
+			expression: "-2w",
+			want:       time.Date(2024, time.July, 24, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "minus years",
+			expression: "-1y",
+			want:       time.Date(2023, time.August, 7, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "days ago",
+			expression: "3 days ago",
+			want:       time.Date(2024, time.August, 4, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "in weeks",
+			expression: "in 1 week",
+			want:       time.Date(2024, time.August, 14, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "empty",
+			expression: "",
+			wantErr:    true,
+		},
+		{
+			name:       "unrecognised",
+			expression: "the day after the party",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid offset unit",
+			expression: "+3q",
+			wantErr:    true,
+		},
+		{
+			name:       "both in and ago",
+			expression: "in 3 days ago",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.expression, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package digest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/frontmatter"
+	"github.com/matthewchivers/journal/pkg/templating"
+)
+
+// DefaultTemplate is the template used to render digests if none is configured
+const DefaultTemplate = `# {{.Title}}
+
+## Contents
+
+{{range .Entries}}- [{{.Title}}](#{{.Anchor}})
+{{end}}{{range .Entries}}
+## {{.Title}}
+
+{{.Content}}
+{{end}}`
+
+// headingDemotion is the number of levels entry headings are demoted by, to nest them under their entry's heading
+const headingDemotion = 2
+
+// maxHeadingLevel is the deepest Markdown heading level
+const maxHeadingLevel = 6
+
+// headingRegex matches a Markdown (ATX) heading
+var headingRegex = regexp.MustCompile(`^(#{1,6})(\s)`)
+
+// anchorRegex matches the characters removed from headings to create their anchors
+var anchorRegex = regexp.MustCompile(`[^\p{L}\p{N}\s_-]`)
+
+// Entry is a journal entry included in a digest
+type Entry struct {
+	// Record is the entry's file
+	catalog.Record
+
+	// Title is the heading of the entry in the digest (e.g. "Mon 12 Aug 2024: standup")
+	Title string
+
+	// Anchor is the link target of the entry's heading (e.g. "mon-12-aug-2024-standup")
+	Anchor string
+
+	// Content is the content of the entry, without its front matter, and with its headings nested under the
+	// entry's heading
+	Content string
+}
+
+// Model is the data available to digest templates: the pattern data for the start of the period, and the
+// entries in the period
+type Model struct {
+	templating.TemplateModel
+
+	// Period is the length of the period (week, month or year)
+	Period Period
+
+	// Start is the first day of the period
+	Start time.Time
+
+	// End is the last day of the period
+	End time.Time
+
+	// Title is the title of the digest (e.g. "Week of 12 Aug 2024")
+	Title string
+
+	// Entries are the entries in the period, in date order
+	Entries []Entry
+}
+
+// NewModel creates the model for a digest of the entries of the period starting on start
+// The records must be sorted by date
+func NewModel(data templating.TemplateModel, period Period, start time.Time, records []catalog.Record) (*Model, error) {
+	_, end := period.Bounds(start)
+	model := &Model{
+		TemplateModel: data,
+		Period:        period,
+		Start:         start,
+		End:           end,
+		Title:         title(period, start),
+		Entries:       []Entry{},
+	}
+	anchors := map[string]int{}
+	for _, record := range records {
+		if record.Encrypted {
+			// Digests are not encrypted, so encrypted entries are left out
+			continue
+		}
+		content, ok, err := catalog.ReadRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		entryTitle := entryTitle(record)
+		model.Entries = append(model.Entries, Entry{
+			Record:  record,
+			Title:   entryTitle,
+			Anchor:  anchor(entryTitle, anchors),
+			Content: entryContent(content),
+		})
+	}
+	return model, nil
+}
+
+// Render renders the digest with the given template
+func (m *Model) Render(name string, document string) (string, error) {
+	return templating.RenderDocument(name, document, m)
+}
+
+// title returns the title of the digest of a period
+func title(period Period, start time.Time) string {
+	switch period {
+	case PeriodMonth:
+		return start.Format("January 2006")
+	case PeriodYear:
+		return start.Format("2006")
+	default:
+		return "Week of " + start.Format("2 January 2006")
+	}
+}
+
+// entryTitle returns the heading of an entry: its date, entry ID and topic
+func entryTitle(record catalog.Record) string {
+	parts := []string{}
+	if !record.Date.IsZero() {
+		parts = append(parts, record.Date.Format("Mon 2 Jan 2006:"))
+	}
+	parts = append(parts, record.EntryID)
+	if record.Topic != "" {
+		parts = append(parts, "("+record.Topic+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// anchor returns the link target of a heading, as generated by GitHub and most Markdown renderers:
+// lower case, without punctuation, with spaces replaced by "-", and numbered if the heading is repeated
+func anchor(heading string, seen map[string]int) string {
+	slug := anchorRegex.ReplaceAllString(strings.ToLower(heading), "")
+	slug = strings.ReplaceAll(slug, " ", "-")
+	count := seen[slug]
+	seen[slug]++
+	if count > 0 {
+		return fmt.Sprintf("%s-%d", slug, count)
+	}
+	return slug
+}
+
+// entryContent returns the content of an entry for the digest: without its front matter, trimmed, and with its
+// headings demoted to nest under the entry's heading (headings within fenced code blocks are left alone)
+func entryContent(content string) string {
+	lines := strings.Split(strings.TrimSpace(frontmatter.Body(content)), "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if match := headingRegex.FindStringSubmatch(line); match != nil {
+			level := min(len(match[1])+headingDemotion, maxHeadingLevel)
+			lines[i] = strings.Repeat("#", level) + line[len(match[1]):]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
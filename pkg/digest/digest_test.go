@@ -0,0 +1,84 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/catalog/catalogtest"
+	"github.com/matthewchivers/journal/pkg/templating"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePeriod(t *testing.T) {
+	period, err := ParsePeriod("month")
+	assert.NoError(t, err)
+	assert.Equal(t, PeriodMonth, period)
+
+	_, err = ParsePeriod("fortnight")
+	assert.Error(t, err)
+}
+
+func TestBounds(t *testing.T) {
+	date := time.Date(2024, time.February, 15, 9, 30, 0, 0, time.UTC)
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		period    Period
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{period: PeriodWeek, wantStart: day(time.February, 12), wantEnd: day(time.February, 18)},
+		{period: PeriodMonth, wantStart: day(time.February, 1), wantEnd: day(time.February, 29)},
+		{period: PeriodYear, wantStart: day(time.January, 1), wantEnd: day(time.December, 31)},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			start, end := tt.period.Bounds(date)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestNewModel(t *testing.T) {
+	tempDir := t.TempDir()
+	records := catalogtest.WriteFiles(t, tempDir,
+		catalogtest.File{
+			Name:    "standup.md",
+			Record:  catalog.Record{EntryID: "standup", Date: time.Date(2024, time.August, 12, 0, 0, 0, 0, time.UTC)},
+			Content: "---\ndate: 2024-08-12\n---\n# Stand-up\n\n```\n# not a heading\n```\n",
+		},
+		catalogtest.File{
+			Name:    "planning.md",
+			Record:  catalog.Record{EntryID: "meeting", Topic: "planning", Date: time.Date(2024, time.August, 13, 0, 0, 0, 0, time.UTC)},
+			Content: "## Actions\n- [ ] send minutes\n",
+		},
+		catalogtest.File{
+			Name:    "planning-2.md",
+			Record:  catalog.Record{EntryID: "meeting", Topic: "planning", Date: time.Date(2024, time.August, 13, 0, 0, 0, 0, time.UTC)},
+			Content: "##### Deep heading\n",
+		},
+	)
+
+	start := time.Date(2024, time.August, 12, 0, 0, 0, 0, time.UTC)
+	data, err := templating.PrepareTemplateData(start)
+	assert.NoError(t, err)
+	model, err := NewModel(data, PeriodWeek, start, records)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.August, 18, 0, 0, 0, 0, time.UTC), model.End)
+
+	got, err := model.Render("digest", DefaultTemplate)
+	assert.NoError(t, err)
+	want := "# Week of 12 August 2024\n\n## Contents\n\n" +
+		"- [Mon 12 Aug 2024: standup](#mon-12-aug-2024-standup)\n" +
+		"- [Tue 13 Aug 2024: meeting (planning)](#tue-13-aug-2024-meeting-planning)\n" +
+		"- [Tue 13 Aug 2024: meeting (planning)](#tue-13-aug-2024-meeting-planning-1)\n" +
+		"\n## Mon 12 Aug 2024: standup\n\n### Stand-up\n\n```\n# not a heading\n```\n" +
+		"\n## Tue 13 Aug 2024: meeting (planning)\n\n#### Actions\n- [ ] send minutes\n" +
+		"\n## Tue 13 Aug 2024: meeting (planning)\n\n###### Deep heading\n"
+	assert.Equal(t, want, got)
+
+	got, err = model.Render("custom", "{{.Period}} {{.Year.Num}}: {{len .Entries}} entries")
+	assert.NoError(t, err)
+	assert.Equal(t, "week 2024: 3 entries", got)
+}
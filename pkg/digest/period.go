@@ -0,0 +1,48 @@
+package digest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/caltools"
+)
+
+// Period is the length of time a digest covers
+type Period string
+
+const (
+	// PeriodWeek is a week, from Monday to Sunday
+	PeriodWeek Period = "week"
+
+	// PeriodMonth is a calendar month
+	PeriodMonth Period = "month"
+
+	// PeriodYear is a calendar year
+	PeriodYear Period = "year"
+)
+
+// ParsePeriod parses the name of a period (week, month or year)
+func ParsePeriod(name string) (Period, error) {
+	switch period := Period(name); period {
+	case PeriodWeek, PeriodMonth, PeriodYear:
+		return period, nil
+	default:
+		return "", fmt.Errorf("unknown period %q (expected week, month or year)", name)
+	}
+}
+
+// Bounds returns the first and last days (at midnight) of the period containing the given date
+func (p Period) Bounds(date time.Time) (time.Time, time.Time) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	switch p {
+	case PeriodMonth:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(0, 1, -1)
+	case PeriodYear:
+		start := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(1, 0, -1)
+	default:
+		start := caltools.WeekCommencing(day)
+		return start, start.AddDate(0, 0, 6)
+	}
+}
@@ -0,0 +1,172 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+)
+
+// Command is an editor launched from a command template
+// Each argument of the template is rendered with the fields of CommandModel,
+// e.g. "vim +{{.Line}} {{.Path}}" or "subl {{.Path}}:{{.Line}}:{{.Column}}"
+// If no argument refers to {{.Path}}, the path is appended as the final argument
+type Command struct {
+	// Name is the name of the editor (used for logging)
+	Name string
+
+	// Template is the command template used to launch the editor
+	Template string
+
+	// args are the arguments of the command template (the first being the executable)
+	args []*template.Template
+
+	// hasPath is true if any argument of the command template refers to the path
+	hasPath bool
+
+	// returnsEarly is true if the command returns before the file is closed (e.g. a GUI editor launched
+	// without its wait flag)
+	returnsEarly bool
+}
+
+// CommandModel contains the fields available to editor command templates
+type CommandModel struct {
+	// Path is the full path to the file to open
+	Path string
+
+	// Line is the line to place the cursor on (starting from 1)
+	Line int
+
+	// Column is the column to place the cursor on (starting from 1)
+	Column int
+}
+
+// NewCommandEditor creates a new editor from a command template
+func NewCommandEditor(name string, commandTemplate string) (*Command, error) {
+	fields, err := splitArgs(commandTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid editor command %q: %w", commandTemplate, err)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("editor command is empty")
+	}
+	cmd := &Command{Name: name, Template: commandTemplate}
+	for i, field := range fields {
+		arg, err := template.New(fmt.Sprintf("arg%d", i)).Option("missingkey=error").Parse(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid editor command %q: %w", commandTemplate, err)
+		}
+		cmd.args = append(cmd.args, arg)
+		if strings.Contains(field, ".Path") {
+			cmd.hasPath = true
+		}
+	}
+	return cmd, nil
+}
+
+// Blocks reports whether OpenFile returns only once the editor has closed the file
+// Command templates are run until they exit, so are assumed to block (terminal editors always do, and GUI
+// editors do with their wait flag)
+func (c *Command) Blocks() bool {
+	return !c.returnsEarly
+}
+
+// OpenFile opens a file in the editor
+func (c *Command) OpenFile(filePath string) error {
+	return c.OpenFileAt(filePath, 1, 1)
+}
+
+// OpenFileAt opens a file in the editor, placing the cursor at the given line and column
+// The editor is attached to the terminal, so terminal editors (e.g. vim) block until they exit
+func (c *Command) OpenFileAt(filePath string, line int, column int) error {
+	logger.Log.Info().Str("file_path", filePath).
+		Str("editor", c.Name).
+		Msg("opening file in editor")
+
+	// *** Security - Path Traversal ***
+	// Validate path to ensure it is safe and does not contain any malicious content
+	if err := validatePath(filePath); err != nil {
+		logger.Log.Err(err).Str("file_path", filePath).
+			Msg("error validating file path")
+		return err
+	}
+
+	args, err := c.buildArgs(CommandModel{Path: filePath, Line: line, Column: column})
+	if err != nil {
+		return err
+	}
+
+	// #nosec G204: Subprocess launched with a potential tainted input or cmd arguments
+	// The command is configured by the user, the path has been validated, and no shell is involved
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		logger.Log.Err(err).Str("file_path", filePath).
+			Str("editor", c.Name).
+			Strs("command", args).
+			Msg("error opening file in editor")
+		return err
+	}
+	logger.Log.Info().Str("file_path", filePath).
+		Str("editor", c.Name).
+		Msg("opened file in editor")
+	return nil
+}
+
+// buildArgs renders the command template into the arguments used to launch the editor
+func (c *Command) buildArgs(model CommandModel) ([]string, error) {
+	args := []string{}
+	for _, arg := range c.args {
+		var rendered strings.Builder
+		if err := arg.Execute(&rendered, model); err != nil {
+			return nil, fmt.Errorf("failed to render editor command: %w", err)
+		}
+		args = append(args, rendered.String())
+	}
+	if !c.hasPath {
+		args = append(args, model.Path)
+	}
+	return args, nil
+}
+
+// splitArgs splits a command into arguments on whitespace, keeping quoted (single or double) text together
+func splitArgs(command string) ([]string, error) {
+	args := []string{}
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	for _, r := range command {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
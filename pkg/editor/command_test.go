@@ -0,0 +1,138 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{name: "single word", command: "vim", want: []string{"vim"}},
+		{name: "multiple words", command: "code  --wait {{.Path}}", want: []string{"code", "--wait", "{{.Path}}"}},
+		{name: "double quotes", command: `vim "+call cursor(1, 2)" file`, want: []string{"vim", "+call cursor(1, 2)", "file"}},
+		{name: "single quotes", command: `'/Applications/My Editor' -n`, want: []string{"/Applications/My Editor", "-n"}},
+		{name: "empty quotes", command: `editor ""`, want: []string{"editor", ""}},
+		{name: "empty", command: "   ", want: []string{}},
+		{name: "unterminated quote", command: `vim "+call`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitArgs(tt.command)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommandBuildArgs(t *testing.T) {
+	model := CommandModel{Path: "/journal/notes/note.md", Line: 12, Column: 3}
+	tests := []struct {
+		name    string
+		setting string
+		wait    bool
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "vim preset",
+			setting: "vim",
+			want:    []string{"vim", "+call cursor(12, 3)", "/journal/notes/note.md"},
+		},
+		{
+			name:    "helix preset",
+			setting: "Helix",
+			want:    []string{"hx", "/journal/notes/note.md:12:3"},
+		},
+		{
+			name:    "sublime preset",
+			setting: "sublime",
+			want:    []string{"subl", "/journal/notes/note.md:12:3"},
+		},
+		{
+			name:    "sublime preset - wait",
+			setting: "sublime",
+			wait:    true,
+			want:    []string{"subl", "--wait", "/journal/notes/note.md:12:3"},
+		},
+		{
+			name:    "terminal preset - wait",
+			setting: "nano",
+			wait:    true,
+			want:    []string{"nano", "+12,3", "/journal/notes/note.md"},
+		},
+		{
+			name:    "command template",
+			setting: "gvim --remote-tab +{{.Line}} {{.Path}}",
+			want:    []string{"gvim", "--remote-tab", "+12", "/journal/notes/note.md"},
+		},
+		{
+			name:    "command without path appends the path",
+			setting: "code --wait",
+			want:    []string{"code", "--wait", "/journal/notes/note.md"},
+		},
+		{
+			name:    "unknown field",
+			setting: "ed {{.Row}} {{.Path}}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ed, err := NewEditor(tt.setting, tt.wait)
+			assert.NoError(t, err)
+			cmd, ok := ed.(*Command)
+			assert.True(t, ok, "editor should be a command editor")
+			got, err := cmd.buildArgs(model)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewEditor(t *testing.T) {
+	ed, err := NewEditor("vscode", true)
+	assert.NoError(t, err)
+	assert.Equal(t, &VSCode{Wait: true}, ed)
+
+	assert.True(t, ed.Blocks())
+
+	ed, err = NewEditor("vscode", false)
+	assert.NoError(t, err)
+	assert.False(t, ed.Blocks(), "VS Code returns as soon as the file is opened")
+
+	ed, err = NewEditor("sublime", false)
+	assert.NoError(t, err)
+	assert.False(t, ed.Blocks(), "Sublime Text returns as soon as the file is opened")
+
+	ed, err = NewEditor("sublime", true)
+	assert.NoError(t, err)
+	assert.True(t, ed.Blocks())
+
+	ed, err = NewEditor("vim", false)
+	assert.NoError(t, err)
+	assert.True(t, ed.Blocks(), "terminal editors always block")
+
+	ed, err = NewEditor("/usr/local/bin/edit {{.Path}}", false)
+	assert.NoError(t, err)
+	assert.True(t, ed.Blocks(), "command templates are run until they exit")
+
+	_, err = NewEditor(`vim "+call`, false)
+	assert.Error(t, err)
+
+	_, err = NewEditor("{{.Path", false)
+	assert.Error(t, err)
+}
@@ -1,5 +1,58 @@
 package editor
 
+import (
+	"strings"
+)
+
 type Editor interface {
 	OpenFile(filePath string) error
+
+	// Blocks reports whether OpenFile returns only once the editor has closed the file
+	Blocks() bool
+}
+
+// preset contains the command templates for a built-in editor
+type preset struct {
+	// command is the command template used to open files
+	command string
+
+	// waitCommand is the command template used to open files and wait for them to be closed
+	// (terminal editors always block until they exit, so only GUI editors need one)
+	waitCommand string
+}
+
+// presets are the built-in editors, by name
+var presets = map[string]preset{
+	"vim":     {command: `vim "+call cursor({{.Line}}, {{.Column}})" {{.Path}}`},
+	"nvim":    {command: `nvim "+call cursor({{.Line}}, {{.Column}})" {{.Path}}`},
+	"nano":    {command: `nano +{{.Line}},{{.Column}} {{.Path}}`},
+	"emacs":   {command: `emacs +{{.Line}}:{{.Column}} {{.Path}}`},
+	"helix":   {command: `hx {{.Path}}:{{.Line}}:{{.Column}}`},
+	"micro":   {command: `micro +{{.Line}}:{{.Column}} {{.Path}}`},
+	"sublime": {command: `subl {{.Path}}:{{.Line}}:{{.Column}}`, waitCommand: `subl --wait {{.Path}}:{{.Line}}:{{.Column}}`},
+}
+
+// NewEditor creates an editor from an editor setting
+// The setting is either the name of a built-in editor ("vscode", "vim", "nvim", "nano", "emacs",
+// "helix", "micro" or "sublime"), or a command template (see Command)
+// If wait is true, built-in editors block until the file is closed; command templates are used as
+// they are, so must include any flag the editor needs to wait
+func NewEditor(setting string, wait bool) (Editor, error) {
+	name := strings.ToLower(strings.TrimSpace(setting))
+	if name == "vscode" {
+		return NewVSCodeEditor(wait)
+	}
+	if p, ok := presets[name]; ok {
+		if wait && p.waitCommand != "" {
+			return NewCommandEditor(name, p.waitCommand)
+		}
+		cmd, err := NewCommandEditor(name, p.command)
+		if err != nil {
+			return nil, err
+		}
+		// GUI editors return as soon as the file is opened, unless launched with their wait command
+		cmd.returnsEarly = p.waitCommand != ""
+		return cmd, nil
+	}
+	return NewCommandEditor(setting, setting)
 }
@@ -10,11 +10,19 @@ import (
 	"github.com/matthewchivers/journal/pkg/logger"
 )
 
-type VSCode struct{}
+type VSCode struct {
+	// Wait is true if OpenFile should block until the file is closed (code --wait)
+	Wait bool
+}
 
 // NewVSCodeEditor creates a new Visual Studio Code editor
-func NewVSCodeEditor() (*VSCode, error) {
-	return &VSCode{}, nil
+func NewVSCodeEditor(wait bool) (*VSCode, error) {
+	return &VSCode{Wait: wait}, nil
+}
+
+// Blocks reports whether OpenFile returns only once the file is closed (code --wait)
+func (v *VSCode) Blocks() bool {
+	return v.Wait
 }
 
 // OpenFile opens a file in Visual Studio Code
@@ -31,9 +39,14 @@ func (v *VSCode) OpenFile(filePath string) error {
 		return err
 	}
 
+	args := []string{filePath}
+	if v.Wait {
+		args = []string{"--wait", filePath}
+	}
+
 	// #nosec G204: Subprocess launched with a potential tainted input or cmd arguments
 	// The inputs have been validated
-	cmd := exec.Command("code", filePath)
+	cmd := exec.Command("code", args...)
 	if err := cmd.Run(); err != nil {
 		logger.Log.Err(err).Str("file_path", filePath).
 			Str("editor", "Visual Studio Code").
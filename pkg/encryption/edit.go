@@ -0,0 +1,112 @@
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+)
+
+// Edit decrypts an encrypted file to a private temporary file (in a directory only the user can access) and
+// calls edit with its path, e.g. to open it in an editor
+// Once edit returns, the temporary file is re-encrypted over the encrypted file (if its content changed) and
+// securely deleted. If edit removes the temporary file, the encrypted file is removed too
+// The temporary file keeps the inner extension of the file (e.g. .md), so editors recognise its type
+func Edit(key []byte, path string, edit func(tempPath string) error) error {
+	plaintext, err := ReadFile(key, path)
+	if err != nil {
+		return err
+	}
+	tempDir, err := os.MkdirTemp("", "journal-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	tempPath := filepath.Join(tempDir, strings.TrimSuffix(filepath.Base(path), Extension))
+	defer removeTempDir(tempDir)
+	if err := os.WriteFile(tempPath, plaintext, 0600); err != nil {
+		return fmt.Errorf("failed to write decrypted file: %w", err)
+	}
+	logger.Log.Debug().Str("file_path", path).
+		Str("temp_path", tempPath).
+		Msg("decrypted file for editing")
+
+	editErr := edit(tempPath)
+	if err := reencrypt(key, path, tempPath, plaintext); err != nil {
+		return err
+	}
+	return editErr
+}
+
+// reencrypt encrypts the edited temporary file over the encrypted file, if it has changed
+func reencrypt(key []byte, path string, tempPath string, original []byte) error {
+	// #nosec G304: Potential file inclusion via variable
+	// The temporary path is generated by Edit
+	edited, err := os.ReadFile(tempPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove encrypted file: %w", err)
+		}
+		logger.Log.Info().Str("file_path", path).Msg("decrypted file was removed - removed encrypted file")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read decrypted file: %w", err)
+	}
+	if bytes.Equal(edited, original) {
+		return nil
+	}
+	if err := WriteFile(key, path, edited); err != nil {
+		return err
+	}
+	logger.Log.Info().Str("file_path", path).Msg("re-encrypted edited file")
+	return nil
+}
+
+// removeTempDir securely deletes the files in the temporary directory (the decrypted file, and any backup or swap
+// files the editor left beside it), then removes the directory
+func removeTempDir(tempDir string) {
+	err := filepath.WalkDir(tempDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		return shred(path)
+	})
+	if err != nil {
+		logger.Log.Err(err).Str("temp_dir", tempDir).Msg("error deleting decrypted files")
+	}
+	if err := os.RemoveAll(tempDir); err != nil {
+		logger.Log.Err(err).Str("temp_dir", tempDir).Msg("error removing temporary directory")
+	}
+}
+
+// shred overwrites a file with zeros before removing it, so its content does not remain on disk
+// (on copy-on-write and flash storage the old blocks may survive, so the file is also kept in a private directory)
+func shred(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return os.Remove(path)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(make([]byte, info.Size())); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
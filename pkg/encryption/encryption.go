@@ -0,0 +1,124 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extension is the extension added to the names of encrypted files (e.g. review-2024-08-02.md.enc)
+const Extension = ".enc"
+
+// KeySize is the size of encryption keys in bytes (AES-256)
+const KeySize = 32
+
+// magic identifies (the version of) the format of encrypted files, and is authenticated with their content
+var magic = []byte("JOURNAL-AES256GCM-1\n")
+
+// ErrNotEncrypted is returned when decrypting data that was not encrypted by Encrypt
+var ErrNotEncrypted = errors.New("not an encrypted journal file")
+
+// IsEncrypted returns true if the path is that of an encrypted file (it has the Extension)
+func IsEncrypted(path string) bool {
+	return strings.HasSuffix(path, Extension)
+}
+
+// FileExt returns the extension of a file, including the inner extension of encrypted files (e.g. ".md.enc")
+func FileExt(path string) string {
+	if !IsEncrypted(path) {
+		return filepath.Ext(path)
+	}
+	return filepath.Ext(strings.TrimSuffix(path, Extension)) + Extension
+}
+
+// Encrypt encrypts and authenticates plaintext with the key (AES-256-GCM)
+// The result is the format identifier, followed by a random nonce and the ciphertext
+func Encrypt(key []byte, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	data := append(append([]byte{}, magic...), nonce...)
+	return aead.Seal(data, nonce, plaintext, magic), nil
+}
+
+// Decrypt decrypts data encrypted by Encrypt with the key, failing if it has been tampered with
+func Decrypt(key []byte, data []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, magic) || len(data) < len(magic)+aead.NonceSize() {
+		return nil, ErrNotEncrypted
+	}
+	data = data[len(magic):]
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, magic)
+	if err != nil {
+		return nil, errors.New("failed to decrypt: wrong key, or the file has been modified")
+	}
+	return plaintext, nil
+}
+
+// newAEAD creates the AES-256-GCM cipher for the key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size: %d bytes (expected %d)", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ReadFile reads and decrypts an encrypted file
+func ReadFile(key []byte, path string) ([]byte, error) {
+	// #nosec G304: Potential file inclusion via variable
+	// The path is that of a journal entry, generated by the application
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := Decrypt(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return plaintext, nil
+}
+
+// WriteFile encrypts plaintext and writes it to path (readable only by the user)
+// The file is replaced atomically, so an interrupted write never leaves a partly written file
+func WriteFile(key []byte, path string, plaintext []byte) error {
+	data, err := Encrypt(key, plaintext)
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package encryption
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey() []byte {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return key
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := testKey()
+	plaintext := []byte("# 1:1\n\nSensitive notes\n")
+
+	data, err := Encrypt(key, plaintext)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "Sensitive")
+	again, err := Encrypt(key, plaintext)
+	assert.NoError(t, err)
+	assert.NotEqual(t, data, again, "each encryption uses a new nonce")
+
+	got, err := Decrypt(key, data)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, got)
+
+	otherKey := testKey()
+	otherKey[0] = 0xff
+	_, err = Decrypt(otherKey, data)
+	assert.Error(t, err)
+
+	tampered := append([]byte{}, data...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = Decrypt(key, tampered)
+	assert.Error(t, err)
+
+	_, err = Decrypt(key, plaintext)
+	assert.ErrorIs(t, err, ErrNotEncrypted)
+
+	_, err = Encrypt(key[:16], plaintext)
+	assert.Error(t, err)
+}
+
+func TestFileExt(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/journal/review-2024-08-02.md", want: ".md"},
+		{path: "/journal/review-2024-08-02.md.enc", want: ".md.enc"},
+		{path: "/journal/review.enc", want: ".enc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, FileExt(tt.path))
+			assert.Equal(t, tt.want != ".md", IsEncrypted(tt.path))
+		})
+	}
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+	keyPath := filepath.Join(t.TempDir(), "keys", "journal.key")
+
+	_, err := LoadKey(keyPath)
+	assert.Error(t, err)
+
+	key, created, err := LoadOrCreateKey(keyPath)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Len(t, key, KeySize)
+	info, err := os.Stat(keyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, created, err := LoadOrCreateKey(keyPath)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, key, loaded)
+
+	assert.NoError(t, os.WriteFile(keyPath, []byte("not a key\n"), 0600))
+	_, err = LoadKey(keyPath)
+	assert.Error(t, err)
+}
+
+func TestEdit(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+	key := testKey()
+	path := filepath.Join(t.TempDir(), "review.md.enc")
+	assert.NoError(t, WriteFile(key, path, []byte("# Review\n")))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	var tempPath string
+	err = Edit(key, path, func(p string) error {
+		tempPath = p
+		content, err := os.ReadFile(p)
+		assert.NoError(t, err)
+		assert.Equal(t, "# Review\n", string(content))
+		assert.Equal(t, "review.md", filepath.Base(p))
+		dirInfo, err := os.Stat(filepath.Dir(p))
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0700), dirInfo.Mode().Perm())
+		// Editors may leave backup files beside the file
+		assert.NoError(t, os.WriteFile(p+"~", content, 0600))
+		return os.WriteFile(p, []byte("# Review\n\nDone\n"), 0600)
+	})
+	assert.NoError(t, err)
+	assert.NoDirExists(t, filepath.Dir(tempPath))
+	content, err := ReadFile(key, path)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Review\n\nDone\n", string(content))
+
+	err = Edit(key, path, func(p string) error {
+		return os.Remove(p)
+	})
+	assert.NoError(t, err)
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,62 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+)
+
+// LoadKey reads the key from a key file: KeySize bytes, hex encoded
+func LoadKey(keyPath string) ([]byte, error) {
+	// #nosec G304: Potential file inclusion via variable
+	// The key path is set by the user's configuration
+	content, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key: %w", err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil || len(key) != KeySize {
+		return nil, fmt.Errorf("invalid encryption key in %s: expected %d hex encoded bytes", keyPath, KeySize)
+	}
+	if info, err := os.Stat(keyPath); err == nil && info.Mode().Perm()&0077 != 0 {
+		logger.Log.Warn().Str("key_path", keyPath).
+			Str("permissions", info.Mode().Perm().String()).
+			Msg("encryption key is readable by other users")
+	}
+	return key, nil
+}
+
+// LoadOrCreateKey reads the key from a key file, generating a new random key (readable only by the user) if
+// the file does not exist
+// Returns true if a new key was created
+func LoadOrCreateKey(keyPath string) ([]byte, bool, error) {
+	if _, err := os.Stat(keyPath); !errors.Is(err, os.ErrNotExist) {
+		key, err := LoadKey(keyPath)
+		return key, false, err
+	}
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, false, fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, false, fmt.Errorf("failed to create key directory: %w", err)
+	}
+	// O_EXCL: never overwrite a key created in the meantime
+	file, err := os.OpenFile(keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create encryption key: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		return nil, false, fmt.Errorf("failed to write encryption key: %w", err)
+	}
+	logger.Log.Info().Str("key_path", keyPath).Msg("created encryption key")
+	return key, true, nil
+}
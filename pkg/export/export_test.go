@@ -0,0 +1,174 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/catalog/catalogtest"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "client-x", slug("Client X"))
+	assert.Equal(t, "project-alpha", slug("project/alpha"))
+	assert.Equal(t, "untitled", slug("!!"))
+}
+
+func TestPageURL(t *testing.T) {
+	used := map[string]bool{}
+	date := time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2024/08/02/standup.html", pageURL(catalog.Record{EntryID: "standup", Date: date}, used))
+	assert.Equal(t, "2024/08/02/standup-2.html", pageURL(catalog.Record{EntryID: "standup", Date: date}, used))
+	assert.Equal(t, "2024/08/02/meeting-planning.html",
+		pageURL(catalog.Record{EntryID: "meeting", Topic: "Planning", Date: date}, used))
+	assert.Equal(t, "undated/note-ideas.html", pageURL(catalog.Record{EntryID: "note", Path: "/notes/ideas.md"}, used))
+}
+
+func TestGroupTags(t *testing.T) {
+	first := &Page{Record: catalog.Record{Tags: []string{"work", "Work"}}}
+	second := &Page{Record: catalog.Record{Tags: []string{"Work"}}}
+	groups := groupTags([]*Page{first, second})
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "tags/work.html", groups[0].URL)
+	assert.Equal(t, []*Page{first}, groups[0].Pages)
+	assert.Equal(t, "tags/work-2.html", groups[1].URL)
+	assert.Equal(t, []*Page{first, second}, groups[1].Pages)
+	assert.Equal(t, groups, first.TagGroups)
+	assert.Equal(t, []*Group{groups[1]}, second.TagGroups)
+}
+
+func TestCalendar(t *testing.T) {
+	// August 2024 starts on a Thursday, and has 31 days
+	page := &Page{Record: catalog.Record{Date: time.Date(2024, time.August, 13, 0, 0, 0, 0, time.UTC)}}
+	weeks := calendar([]*Page{page})
+	assert.Len(t, weeks, 5)
+	assert.Equal(t, 0, weeks[0][2].Day)
+	assert.Equal(t, 1, weeks[0][3].Day)
+	assert.Equal(t, 13, weeks[2][1].Day)
+	assert.Equal(t, []*Page{page}, weeks[2][1].Pages)
+	assert.Equal(t, 31, weeks[4][5].Day)
+	assert.Equal(t, 0, weeks[4][6].Day)
+}
+
+func TestExportHTML(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	journalDir := t.TempDir()
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	records := catalogtest.WriteFiles(t, journalDir,
+		catalogtest.File{
+			Name:    "standup-0731.md",
+			Record:  catalog.Record{EntryID: "standup", Date: day(time.July, 31), Tags: []string{"work"}},
+			Content: "---\ndate: 2024-07-31\n---\n# Stand-up\n\n- [ ] write report\n",
+		},
+		catalogtest.File{
+			Name:    "planning-0801.md",
+			Record:  catalog.Record{EntryID: "meeting", Topic: "planning", Date: day(time.August, 1)},
+			Content: "Notes <script>alert(1)</script>\n",
+		},
+		catalogtest.File{
+			Name:    "standup-0802.md",
+			Record:  catalog.Record{EntryID: "standup", Date: day(time.August, 2)},
+			Content: "| a | b |\n|---|---|\n| 1 | 2 |\n",
+		},
+		catalogtest.File{
+			Name:    "ideas.md",
+			Record:  catalog.Record{EntryID: "note"},
+			Content: "undated",
+		},
+	)
+
+	pages, err := NewPages(records)
+	assert.NoError(t, err)
+	site := NewSite("My Journal", pages)
+
+	assert.Len(t, site.Years, 1)
+	assert.Len(t, site.Years[0].Months, 2)
+	assert.Equal(t, "August 2024", site.Years[0].Months[1].Title)
+	assert.Equal(t, "July 2024", site.Years[0].Months[1].Prev.Title)
+	assert.Len(t, site.Weeks, 1)
+	assert.Equal(t, "Week of 29th July 2024", site.Weeks[0].Title)
+	assert.Equal(t, []string{"meeting", "note", "standup"}, groupTitles(site.EntryTypes))
+	assert.Equal(t, []string{"work"}, groupTitles(site.Tags))
+	assert.Equal(t, "undated/note-ideas.html", site.Undated[0].URL)
+
+	standup := site.Pages[0]
+	assert.Equal(t, "Wednesday 31st July 2024: standup", standup.Title)
+	assert.Equal(t, site.Pages[1], standup.Next)
+	assert.Equal(t, site.Pages[2], standup.NextOfType)
+	assert.Nil(t, standup.PrevOfType)
+
+	outDir := t.TempDir()
+	assert.NoError(t, site.WriteHTML(outDir))
+	for _, url := range []string{
+		"index.html", "2024/index.html", "2024/07/index.html", "2024/08/index.html", "weeks/index.html",
+		"weeks/2024-07-29.html", "entries/index.html", "entries/standup.html", "tags/index.html", "tags/work.html",
+		"2024/07/31/standup.html", "2024/08/01/meeting-planning.html", "undated/note-ideas.html",
+	} {
+		assert.FileExists(t, filepath.Join(outDir, url))
+	}
+
+	entry := readFile(t, filepath.Join(outDir, "2024/07/31/standup.html"))
+	assert.Contains(t, entry, `<h1>Stand-up</h1>`)
+	assert.Contains(t, entry, `<input disabled="" type="checkbox"> write report`)
+	assert.Contains(t, entry, `href="../../../tags/work.html"`)
+	assert.Contains(t, entry, `href="../../../2024/08/01/meeting-planning.html"`)
+	assert.NotContains(t, entry, "date: 2024-07-31")
+
+	assert.NotContains(t, readFile(t, filepath.Join(outDir, "2024/08/01/meeting-planning.html")), "<script>")
+	assert.Contains(t, readFile(t, filepath.Join(outDir, "2024/08/02/standup.html")), "<table>")
+	assert.Contains(t, readFile(t, filepath.Join(outDir, "index.html")), `href="2024/08/index.html"`)
+}
+
+func TestRenderPage(t *testing.T) {
+	journalDir := t.TempDir()
+	records := catalogtest.WriteFiles(t, journalDir, catalogtest.File{
+		Name:    "a.md",
+		Record:  catalog.Record{EntryID: "standup", Date: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)},
+		Content: "# Stand-up\n",
+	})
+	pages, err := NewPages(records)
+	assert.NoError(t, err)
+	site := NewSite("My Journal", pages)
+	site.Live = true
+
+	renderer, err := NewRenderer(map[string]string{"extra": `{{define "content"}}<p>{{.Data}}</p>{{end}}`})
+	assert.NoError(t, err)
+
+	var page strings.Builder
+	found, err := renderer.RenderPage(&page, site, "2024/08/02/standup.html")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Contains(t, page.String(), "<h1>Stand-up</h1>")
+	assert.Contains(t, page.String(), `action="../../../search"`)
+
+	found, err = renderer.RenderPage(&page, site, "2024/08/03/standup.html")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	page.Reset()
+	assert.NoError(t, renderer.Render(&page, "extra", "search", PageData{Site: site, Title: "Extra", Data: "<b>"}))
+	assert.Contains(t, page.String(), "<p>&lt;b&gt;</p>")
+	assert.Contains(t, page.String(), "<title>Extra - My Journal</title>")
+}
+
+func groupTitles(groups []*Group) []string {
+	titles := []string{}
+	for _, group := range groups {
+		titles = append(titles, group.Title)
+	}
+	return titles
+}
+
+func readFile(t *testing.T, filePath string) string {
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	return strings.TrimSpace(string(content))
+}
@@ -0,0 +1,245 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/frontmatter"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/templating"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdown converts Markdown to HTML (CommonMark with GitHub's extensions: tables, task lists, etc.)
+// Raw HTML in entries is omitted
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// PageData is the data available to the templates of the site's pages
+type PageData struct {
+	// Site is the site the page belongs to
+	Site *Site
+
+	// Title is the title of the page
+	Title string
+
+	// Page is the entry shown on an entry page
+	Page *Page
+
+	// Group is the group shown on a group page (a week, entry type or tag)
+	Group *Group
+
+	// Groups are the groups listed on an index of groups (e.g. every tag)
+	Groups []*Group
+
+	// Month is the month shown on a month page
+	Month *Month
+
+	// Year is the year shown on a year page
+	Year *Year
+
+	// Data is the data of pages rendered with extra templates (see NewRenderer)
+	Data interface{}
+}
+
+// route is a page of the site, and the template it is rendered with
+type route struct {
+	// url is the path of the page, relative to the root of the site
+	url string
+
+	// template is the name of the template the page is rendered with
+	template string
+
+	// data is the data the page is rendered with
+	data PageData
+}
+
+// Renderer renders the pages of a site
+type Renderer struct {
+	// templates are the templates of each kind of page (each combined with the layout)
+	templates map[string]*template.Template
+}
+
+// NewPages creates the pages for the entries of the records (which must be sorted by date)
+// Encrypted entries are skipped; pages are rendered with Render
+func NewPages(records []catalog.Record) ([]*Page, error) {
+	pages := []*Page{}
+	used := map[string]bool{}
+	for _, record := range records {
+		if record.Encrypted {
+			continue
+		}
+		page := &Page{Record: record, URL: pageURL(record, used)}
+		if !record.Date.IsZero() {
+			date, err := templating.PrepareTemplateData(record.Date)
+			if err != nil {
+				return nil, err
+			}
+			page.Date = &date
+		}
+		page.Title = pageTitle(record, page.Date)
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// Render renders the page's entry from Markdown (regardless of its file extension), without its front matter
+func (p *Page) Render() error {
+	content, ok, err := catalog.ReadRecord(p.Record)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("failed to read %s: %w", p.Path, os.ErrNotExist)
+	}
+	var rendered bytes.Buffer
+	if err := markdown.Convert([]byte(frontmatter.Body(content)), &rendered); err != nil {
+		return fmt.Errorf("failed to render %s: %w", p.Path, err)
+	}
+	// #nosec G203: The used method does not auto-escape HTML
+	// The HTML was rendered from Markdown, which omits any raw HTML in the entry
+	p.HTML = template.HTML(rendered.String())
+	return nil
+}
+
+// routes returns every page of the site
+func (s *Site) routes() []route {
+	routes := []route{{url: "index.html", template: "home", data: PageData{Title: s.Title}}}
+	for _, page := range s.Pages {
+		routes = append(routes, route{url: page.URL, template: "entry", data: PageData{Title: page.Title, Page: page}})
+	}
+	for _, year := range s.Years {
+		routes = append(routes, route{url: year.URL, template: "year", data: PageData{Title: year.Title, Year: year}})
+		for _, month := range year.Months {
+			routes = append(routes, route{url: month.URL, template: "month", data: PageData{Title: month.Title, Month: month}})
+		}
+	}
+	indexes := []struct {
+		url    string
+		title  string
+		groups []*Group
+	}{
+		{url: "weeks/index.html", title: "Weeks", groups: s.Weeks},
+		{url: "entries/index.html", title: "Entry types", groups: s.EntryTypes},
+		{url: "tags/index.html", title: "Tags", groups: s.Tags},
+	}
+	for _, index := range indexes {
+		routes = append(routes, route{url: index.url, template: "groups", data: PageData{Title: index.title, Groups: index.groups}})
+		for _, group := range index.groups {
+			routes = append(routes, route{url: group.URL, template: "group", data: PageData{Title: group.Title, Group: group}})
+		}
+	}
+	for i := range routes {
+		routes[i].data.Site = s
+	}
+	return routes
+}
+
+// WriteHTML writes the site to the output directory as static HTML pages (index.html is the home page)
+// Links between pages are relative, so the site can be browsed from the file system or copied elsewhere
+// Existing files in the output directory are overwritten, but never removed
+func (s *Site) WriteHTML(outDir string) error {
+	renderer, err := NewRenderer(nil)
+	if err != nil {
+		return err
+	}
+	for _, r := range s.routes() {
+		if r.data.Page != nil {
+			if err := r.data.Page.Render(); err != nil {
+				return err
+			}
+		}
+		if err := renderer.writePage(outDir, r); err != nil {
+			return err
+		}
+	}
+	logger.Log.Debug().Str("out_dir", outDir).
+		Int("pages", len(s.Pages)).
+		Msg("site written")
+	return nil
+}
+
+// NewRenderer parses the templates of each kind of page, plus any extra templates (by name)
+// Extra templates define "content", which is rendered within the layout of the site
+func NewRenderer(extraTemplates map[string]string) (*Renderer, error) {
+	base, err := template.New("layout").Funcs(template.FuncMap{
+		"link": func(url string) string { return url },
+		"slug": slug,
+	}).Parse(layoutTemplate)
+	if err != nil {
+		return nil, err
+	}
+	templates := map[string]*template.Template{}
+	for _, set := range []map[string]string{pageTemplates, extraTemplates} {
+		for name, content := range set {
+			clone, err := base.Clone()
+			if err != nil {
+				return nil, err
+			}
+			if templates[name], err = clone.Parse(content); err != nil {
+				return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+			}
+		}
+	}
+	return &Renderer{templates: templates}, nil
+}
+
+// RenderPage renders the page of the site at the given path (relative to the root of the site)
+// Returns false if the site has no such page
+func (r *Renderer) RenderPage(w io.Writer, s *Site, url string) (bool, error) {
+	for _, route := range s.routes() {
+		if route.url != url {
+			continue
+		}
+		if route.data.Page != nil {
+			if err := route.data.Page.Render(); err != nil {
+				return true, err
+			}
+		}
+		return true, r.Render(w, route.template, url, route.data)
+	}
+	return false, nil
+}
+
+// Render renders a page with the named template, making links relative to the page's path (url)
+func (r *Renderer) Render(w io.Writer, name string, url string, data PageData) error {
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return fmt.Errorf("unknown template: %s", name)
+	}
+	// Templates are cloned so that links can be made relative to the page being rendered
+	// (templates that have been executed cannot be cloned, so the original is never executed)
+	page, err := tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	root := strings.Repeat("../", strings.Count(url, "/"))
+	page.Funcs(template.FuncMap{
+		"link": func(target string) string { return root + target },
+	})
+	if err := page.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", url, err)
+	}
+	return nil
+}
+
+// writePage renders a page and writes it to its path within the output directory
+func (r *Renderer) writePage(outDir string, page route) error {
+	var rendered bytes.Buffer
+	if err := r.Render(&rendered, page.template, page.url, page.data); err != nil {
+		return err
+	}
+	filePath := filepath.Join(outDir, filepath.FromSlash(page.url))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	// #nosec G306: Expect WriteFile permissions to be 0600 or less
+	// The site is meant to be shared (e.g. served by a web server), so it is readable by everyone
+	return os.WriteFile(filePath, rendered.Bytes(), 0644)
+}
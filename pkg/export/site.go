@@ -0,0 +1,365 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/caltools"
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/templating"
+)
+
+// daysInWeek is the number of columns in a calendar
+const daysInWeek = 7
+
+// Page is an exported journal entry
+type Page struct {
+	// Record is the entry's file
+	catalog.Record
+
+	// Title is the title of the page (e.g. "Monday 12th August 2024: standup")
+	Title string
+
+	// URL is the path of the page, relative to the root of the site
+	URL string
+
+	// Date is the date model of the entry (nil for entries without a date)
+	Date *templating.TemplateModel
+
+	// HTML is the entry rendered as HTML
+	HTML template.HTML
+
+	// Prev and Next are the previous and next entries (by date) of the site
+	Prev, Next *Page
+
+	// PrevOfType and NextOfType are the previous and next entries (by date) of the same entry type
+	PrevOfType, NextOfType *Page
+
+	// TagGroups are the groups of the entry's tags, in the order of its tags
+	TagGroups []*Group
+}
+
+// Group is a set of pages listed together on an index page (e.g. the entries of a week, or with a tag)
+type Group struct {
+	// Title is the title of the group
+	Title string
+
+	// URL is the path of the group's index page, relative to the root of the site
+	URL string
+
+	// Pages are the pages in the group, by date
+	Pages []*Page
+
+	// Prev and Next are the previous and next groups of the same kind (e.g. the previous and next week)
+	Prev, Next *Group
+}
+
+// CalendarDay is a day in a month's calendar
+type CalendarDay struct {
+	// Day is the day of the month (0 for the padding before the first and after the last day of the month)
+	Day int
+
+	// Pages are the entries of the day
+	Pages []*Page
+}
+
+// Month is a month of the journal, with a calendar of its entries
+type Month struct {
+	Group
+
+	// Calendar is the weeks of the month (Monday to Sunday)
+	Calendar [][]CalendarDay
+}
+
+// Year is a year of the journal
+type Year struct {
+	Group
+
+	// Months are the months of the year that have entries
+	Months []*Month
+}
+
+// Site is the structure of an exported journal
+type Site struct {
+	// Title is the title of the site
+	Title string
+
+	// Pages are all of the entries, by date (entries without a date last)
+	Pages []*Page
+
+	// Years are the years with entries
+	Years []*Year
+
+	// Weeks are the weeks (Monday to Sunday) with entries
+	Weeks []*Group
+
+	// EntryTypes are the entry types with entries, by ID
+	EntryTypes []*Group
+
+	// Tags are the tags used by entries, by name
+	Tags []*Group
+
+	// Undated are the entries without a date
+	Undated []*Page
+
+	// Live is true if the site is being served by 'journal serve' (which adds search and a form for new entries)
+	Live bool
+}
+
+// NewSite arranges the pages of a site into years, months, weeks, entry types and tags, and links each page to
+// its neighbours
+// The pages must be sorted by date
+func NewSite(title string, pages []*Page) *Site {
+	site := &Site{Title: title}
+	dated := []*Page{}
+	for _, page := range pages {
+		if page.Date == nil {
+			site.Undated = append(site.Undated, page)
+			continue
+		}
+		dated = append(dated, page)
+	}
+	site.Pages = append(dated, site.Undated...)
+	site.Years = groupYears(dated)
+	site.Weeks = linkGroups(groupPages(dated, weekGroup))
+	site.EntryTypes = sortGroups(groupPages(site.Pages, entryTypeGroup))
+	site.Tags = sortGroups(groupTags(site.Pages))
+	linkPages(site.Pages)
+	for _, entryType := range site.EntryTypes {
+		linkPagesOfType(entryType.Pages)
+	}
+	return site
+}
+
+// groupKey returns the key and title of the group a page belongs to
+type groupKey func(page *Page) (key string, title string)
+
+// weekGroup groups pages by the week (commencing Monday) of their date
+func weekGroup(page *Page) (string, string) {
+	wkCom := page.Date.WkCom
+	key := fmt.Sprintf("weeks/%s-%s-%s.html", wkCom.Year.Num, wkCom.Month.Pad, wkCom.Day.Pad)
+	return key, fmt.Sprintf("Week of %s %s %s", wkCom.Day.Ord, wkCom.Month.Name, wkCom.Year.Num)
+}
+
+// entryTypeGroup groups pages by entry type
+func entryTypeGroup(page *Page) (string, string) {
+	return "entries/" + slug(page.EntryID) + ".html", page.EntryID
+}
+
+// groupPages groups pages by key, in order of first appearance
+func groupPages(pages []*Page, key groupKey) []*Group {
+	groups := []*Group{}
+	byKey := map[string]*Group{}
+	for _, page := range pages {
+		url, title := key(page)
+		group, ok := byKey[url]
+		if !ok {
+			group = &Group{Title: title, URL: url}
+			byKey[url] = group
+			groups = append(groups, group)
+		}
+		group.Pages = append(group.Pages, page)
+	}
+	return groups
+}
+
+// groupTags groups pages by each of their tags, and links each page to the groups of its tags
+// Tags with the same slug (e.g. "Work" and "work") are given numbered URLs, so that their pages don't overwrite
+// each other
+func groupTags(pages []*Page) []*Group {
+	groups := []*Group{}
+	byTag := map[string]*Group{}
+	used := map[string]bool{}
+	for _, page := range pages {
+		for _, tag := range page.Tags {
+			group, ok := byTag[tag]
+			if !ok {
+				group = &Group{Title: tag, URL: tagURL(tag, used)}
+				byTag[tag] = group
+				groups = append(groups, group)
+			}
+			group.Pages = append(group.Pages, page)
+			page.TagGroups = append(page.TagGroups, group)
+		}
+	}
+	return groups
+}
+
+// tagURL returns the path of a tag's page (e.g. "tags/work.html")
+// URLs already in use are given a numbered suffix
+func tagURL(tag string, used map[string]bool) string {
+	name := slug(tag)
+	url := path.Join("tags", name+".html")
+	for i := 2; used[url]; i++ {
+		url = path.Join("tags", fmt.Sprintf("%s-%d.html", name, i))
+	}
+	used[url] = true
+	return url
+}
+
+// groupYears groups dated pages by year and month
+func groupYears(pages []*Page) []*Year {
+	years := []*Year{}
+	months := []*Group{}
+	for _, group := range groupPages(pages, func(page *Page) (string, string) {
+		return page.Date.Year.Num + "/index.html", page.Date.Year.Num
+	}) {
+		year := &Year{Group: *group}
+		for _, monthGroup := range groupPages(year.Pages, func(page *Page) (string, string) {
+			return fmt.Sprintf("%s/%s/index.html", page.Date.Year.Num, page.Date.Month.Pad),
+				page.Date.Month.Name + " " + page.Date.Year.Num
+		}) {
+			month := &Month{Group: *monthGroup, Calendar: calendar(monthGroup.Pages)}
+			year.Months = append(year.Months, month)
+			months = append(months, &month.Group)
+		}
+		years = append(years, year)
+	}
+	linkGroups(months)
+	yearGroups := []*Group{}
+	for _, year := range years {
+		yearGroups = append(yearGroups, &year.Group)
+	}
+	linkGroups(yearGroups)
+	return years
+}
+
+// calendar lays out the days of the month of the pages (which must all be in the same month) in weeks,
+// Monday to Sunday
+func calendar(pages []*Page) [][]CalendarDay {
+	first := time.Date(pages[0].Record.Date.Year(), pages[0].Record.Date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	days := make([]CalendarDay, caltools.DaysInMonth(first))
+	for i := range days {
+		days[i].Day = i + 1
+	}
+	for _, page := range pages {
+		day := page.Record.Date.Day()
+		days[day-1].Pages = append(days[day-1].Pages, page)
+	}
+
+	// Pad the calendar to start on a Monday and end on a Sunday
+	cells := make([]CalendarDay, (int(first.Weekday())+6)%daysInWeek)
+	cells = append(cells, days...)
+	for len(cells)%daysInWeek != 0 {
+		cells = append(cells, CalendarDay{})
+	}
+	weeks := [][]CalendarDay{}
+	for i := 0; i < len(cells); i += daysInWeek {
+		weeks = append(weeks, cells[i:i+daysInWeek])
+	}
+	return weeks
+}
+
+// linkGroups links each group to the previous and next groups
+func linkGroups(groups []*Group) []*Group {
+	for i, group := range groups {
+		if i > 0 {
+			group.Prev = groups[i-1]
+		}
+		if i < len(groups)-1 {
+			group.Next = groups[i+1]
+		}
+	}
+	return groups
+}
+
+// sortGroups sorts groups by title
+func sortGroups(groups []*Group) []*Group {
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Title < groups[j].Title
+	})
+	return groups
+}
+
+// linkPages links each page to the previous and next pages
+func linkPages(pages []*Page) {
+	for i, page := range pages {
+		if i > 0 {
+			page.Prev = pages[i-1]
+		}
+		if i < len(pages)-1 {
+			page.Next = pages[i+1]
+		}
+	}
+}
+
+// linkPagesOfType links each page to the previous and next pages of the same entry type
+func linkPagesOfType(pages []*Page) {
+	for i, page := range pages {
+		if i > 0 {
+			page.PrevOfType = pages[i-1]
+		}
+		if i < len(pages)-1 {
+			page.NextOfType = pages[i+1]
+		}
+	}
+}
+
+// pageURL returns the path of an entry's page: under its date (e.g. "2024/08/12/standup.html"), or under
+// "undated" for entries without a date
+// URLs already in use are given a numbered suffix
+func pageURL(record catalog.Record, used map[string]bool) string {
+	name := slug(record.EntryID)
+	if record.Topic != "" {
+		name += "-" + slug(record.Topic)
+	}
+	dir := "undated"
+	if !record.Date.IsZero() {
+		dir = record.Date.Format("2006/01/02")
+	} else {
+		name += "-" + slug(strings.TrimSuffix(path.Base(record.Path), path.Ext(record.Path)))
+	}
+	url := path.Join(dir, name+".html")
+	for i := 2; used[url]; i++ {
+		url = path.Join(dir, fmt.Sprintf("%s-%d.html", name, i))
+	}
+	used[url] = true
+	return url
+}
+
+// pageTitle returns the title of an entry's page: its date, entry ID and topic
+func pageTitle(record catalog.Record, date *templating.TemplateModel) string {
+	title := record.EntryID
+	if record.Topic != "" {
+		title += " (" + record.Topic + ")"
+	}
+	if date == nil {
+		return title
+	}
+	return fmt.Sprintf("%s %s %s %s: %s", date.Day.Name, date.Day.Ord, date.Month.Name, date.Year.Num, title)
+}
+
+// slug returns text in a form safe to use in a URL: lower case letters, numbers and "-"
+func slug(text string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && sb.Len() > 0 {
+			sb.WriteRune('-')
+			dash = true
+		}
+	}
+	result := strings.TrimSuffix(sb.String(), "-")
+	if result == "" {
+		return "untitled"
+	}
+	return result
+}
+
+// Records returns the records of the entries of the site
+func (s *Site) Records() []catalog.Record {
+	records := []catalog.Record{}
+	for _, page := range s.Pages {
+		records = append(records, page.Record)
+	}
+	return records
+}
@@ -0,0 +1,99 @@
+package export
+
+// layoutTemplate is the layout shared by every page of the site: a header with the site navigation, followed by
+// the page's "content" template
+// Links are written with link, which makes them relative to the page being rendered
+const layoutTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if ne .Title .Site.Title}}{{.Title}} - {{end}}{{.Site.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; line-height: 1.5; max-width: 50rem; margin: 0 auto; padding: 1rem; color: #222; }
+header { border-bottom: 1px solid #ddd; margin-bottom: 1rem; padding-bottom: .5rem; }
+header .site { font-weight: bold; font-size: 1.2rem; margin-right: 1rem; }
+nav a, .pager a { margin-right: .75rem; }
+a { color: #0645ad; text-decoration: none; }
+a:hover { text-decoration: underline; }
+.meta { color: #666; font-size: .9rem; }
+.pager { display: flex; justify-content: space-between; border-top: 1px solid #ddd; margin-top: 2rem; padding-top: .5rem; }
+table.calendar { border-collapse: collapse; width: 100%; table-layout: fixed; margin-bottom: 1rem; }
+table.calendar th, table.calendar td { border: 1px solid #ddd; vertical-align: top; padding: .25rem; height: 3.5rem; font-size: .85rem; }
+table.calendar td a { display: block; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+pre { background: #f6f8fa; padding: .75rem; overflow-x: auto; }
+code { background: #f6f8fa; }
+form.search { display: inline; }
+.error { color: #b00020; }
+mark { background: #ffe066; }
+ul.contains-task-list { list-style: none; padding-left: 1.25rem; }
+</style>
+</head>
+<body>
+<header>
+<a class="site" href="{{link "index.html"}}">{{.Site.Title}}</a>
+<nav>{{range .Site.Years}}<a href="{{link .URL}}">{{.Title}}</a>{{end}}<a href="{{link "weeks/index.html"}}">Weeks</a><a href="{{link "entries/index.html"}}">Entry types</a><a href="{{link "tags/index.html"}}">Tags</a>{{if .Site.Live}}<a href="{{link "new"}}">New entry</a>
+<form class="search" action="{{link "search"}}" method="get"><input type="search" name="q" placeholder="Search" aria-label="Search"></form>{{end}}</nav>
+</header>
+<main>
+{{template "content" .}}
+</main>
+</body>
+</html>
+{{- define "pages"}}<ul>
+{{range .}}<li><a href="{{link .URL}}">{{.Title}}</a></li>
+{{end}}</ul>{{end}}
+{{- define "groups"}}<ul>
+{{range .}}<li><a href="{{link .URL}}">{{.Title}}</a> ({{len .Pages}})</li>
+{{end}}</ul>{{end}}
+{{- define "pager"}}<div class="pager"><span>{{with .Prev}}<a href="{{link .URL}}">&larr; {{.Title}}</a>{{end}}</span><span>{{with .Next}}<a href="{{link .URL}}">{{.Title}} &rarr;</a>{{end}}</span></div>{{end}}
+{{- define "calendar"}}<table class="calendar">
+<tr><th>Mon</th><th>Tue</th><th>Wed</th><th>Thu</th><th>Fri</th><th>Sat</th><th>Sun</th></tr>
+{{range .Calendar}}<tr>{{range .}}<td>{{if .Day}}{{.Day}}{{range .Pages}}<a href="{{link .URL}}" title="{{.Title}}">{{.EntryID}}{{with .Topic}} ({{.}}){{end}}</a>{{end}}{{end}}</td>{{end}}</tr>
+{{end}}</table>{{end}}`
+
+// pageTemplates are the "content" templates of each kind of page
+var pageTemplates = map[string]string{
+	"home": `{{define "content"}}<h1>{{.Site.Title}}</h1>
+{{if not .Site.Pages}}<p>There are no entries.</p>{{end}}
+{{range .Site.Years}}<h2><a href="{{link .URL}}">{{.Title}}</a></h2>
+<ul>
+{{range .Months}}<li><a href="{{link .URL}}">{{.Title}}</a> ({{len .Pages}})</li>
+{{end}}</ul>
+{{end}}
+{{with .Site.EntryTypes}}<h2>Entry types</h2>
+{{template "groups" .}}{{end}}
+{{with .Site.Tags}}<h2>Tags</h2>
+{{template "groups" .}}{{end}}
+{{with .Site.Undated}}<h2>Undated</h2>
+{{template "pages" .}}{{end}}{{end}}`,
+
+	"entry": `{{define "content"}}{{with .Page}}<h1>{{.Title}}</h1>
+<p class="meta"><a href="{{link (printf "entries/%s.html" (slug .EntryID))}}">{{.EntryID}}</a>
+{{with .Date}} &middot; <a href="{{link (printf "%s/%s/index.html" .Year.Num .Month.Pad)}}">{{.Month.Name}} {{.Year.Num}}</a>
+&middot; <a href="{{link (printf "weeks/%s-%s-%s.html" .WkCom.Year.Num .WkCom.Month.Pad .WkCom.Day.Pad)}}">week {{.Year.Week.Num}}</a>{{end}}
+{{range .TagGroups}} &middot; <a href="{{link .URL}}">#{{.Title}}</a>{{end}}</p>
+<article>
+{{.HTML}}
+</article>
+{{if or .PrevOfType .NextOfType}}<div class="pager"><span>{{with .PrevOfType}}<a href="{{link .URL}}">&larr; previous {{.EntryID}}</a>{{end}}</span><span>{{with .NextOfType}}<a href="{{link .URL}}">next {{.EntryID}} &rarr;</a>{{end}}</span></div>{{end}}
+{{template "pager" .}}{{end}}{{end}}`,
+
+	"year": `{{define "content"}}{{with .Year}}<h1>{{.Title}}</h1>
+{{range .Months}}<h2><a href="{{link .URL}}">{{.Title}}</a></h2>
+{{template "calendar" .}}
+{{end}}
+{{template "pager" .}}{{end}}{{end}}`,
+
+	"month": `{{define "content"}}{{with .Month}}<h1>{{.Title}}</h1>
+{{template "calendar" .}}
+{{template "pages" .Pages}}
+{{template "pager" .}}{{end}}{{end}}`,
+
+	"group": `{{define "content"}}{{with .Group}}<h1>{{.Title}}</h1>
+{{template "pages" .Pages}}
+{{template "pager" .}}{{end}}{{end}}`,
+
+	"groups": `{{define "content"}}<h1>{{.Title}}</h1>
+{{if .Groups}}{{template "groups" .Groups}}{{else}}<p>None.</p>{{end}}{{end}}`,
+}
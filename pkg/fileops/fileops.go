@@ -1,34 +1,176 @@
 package fileops
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/encryption"
 	"github.com/matthewchivers/journal/pkg/logger"
 )
 
-// CreateNewFile creates a new file based on the provided configuration and document template name
-func CreateNewFile(filePath string) error {
+// maxSuffix is the highest numbered suffix tried before giving up on finding a free file name
+const maxSuffix = 1000
+
+// ExistsOptions control what CreateFile does when the file already exists
+type ExistsOptions struct {
+	// Policy is the onExists policy (see config.OnExists*; empty is treated as open)
+	Policy string
+
+	// Section is the content appended to the existing file by the append-section policy
+	Section string
+
+	// Key is the key encrypted files (see encryption.IsEncrypted) are encrypted with
+	Key []byte
+}
+
+// CreateFile creates a new file at the given path, populated with the provided content, applying the
+// onExists policy if the file already exists
+// Returns the path of the file to open (which differs from filePath for the suffix policy), and whether
+// a new file was created with the provided content
+func CreateFile(filePath string, content string, opts ExistsOptions) (string, bool, error) {
+	info, err := os.Stat(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		created, err := createNewFile(filePath, content, opts.Key)
+		return filePath, created, err
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if info.IsDir() {
+		return "", false, fmt.Errorf("file already exists and is a directory: %s", filePath)
+	}
+	return applyExistsPolicy(filePath, content, opts)
+}
+
+// applyExistsPolicy applies the onExists policy to an existing file (see CreateFile)
+func applyExistsPolicy(filePath string, content string, opts ExistsOptions) (string, bool, error) {
+	switch opts.Policy {
+	case "", config.OnExistsOpen:
+		logger.Log.Info().Str("file_path", filePath).Msg("file already exists - opening it")
+		return filePath, false, nil
+	case config.OnExistsError:
+		return "", false, fmt.Errorf("file already exists: %s", filePath)
+	case config.OnExistsAppendSection:
+		return filePath, false, appendToFile(filePath, opts.Section, opts.Key)
+	case config.OnExistsSuffix:
+		suffixedPath, err := nextFreePath(filePath)
+		if err != nil {
+			return "", false, err
+		}
+		created, err := createNewFile(suffixedPath, content, opts.Key)
+		return suffixedPath, created, err
+	case config.OnExistsOverwrite:
+		if err := backupFile(filePath); err != nil {
+			return "", false, err
+		}
+		// The replaced file is not reported as created, so that it is never removed after editing
+		_, err := createNewFile(filePath, content, opts.Key)
+		return filePath, false, err
+	default:
+		return "", false, fmt.Errorf("unknown onExists policy: %s", opts.Policy)
+	}
+}
+
+// CreateNewFile creates a new file at the given path, populated with the provided content
+// (e.g. a rendered document template)
+// Returns false if the file already existed (in which case it is left untouched)
+func CreateNewFile(filePath string, content string) (bool, error) {
+	return createNewFile(filePath, content, nil)
+}
+
+// createNewFile creates a new file (see CreateNewFile), encrypting its content with the key if the path is that
+// of an encrypted file
+func createNewFile(filePath string, content string, key []byte) (bool, error) {
 	if err := ensureDirectoryExists(filepath.Dir(filePath)); err != nil {
-		return err
+		return false, err
 	}
 	// Check if the file already exists
 	if _, err := os.Stat(filePath); err == nil {
 		// check if the file is a directory
 		if info, err := os.Stat(filePath); err == nil && info.IsDir() {
-			return fmt.Errorf("file already exists and is a directory: %s", filePath)
+			return false, fmt.Errorf("file already exists and is a directory: %s", filePath)
 		}
-		// return fmt.Errorf("file already exists: %s", filePath)
 		logger.Log.Warn().Str("file_path", filePath).Msg("file already exists")
-		return nil
+		return false, nil
+	}
+	if encryption.IsEncrypted(filePath) {
+		if err := encryption.WriteFile(key, filePath, []byte(content)); err != nil {
+			return false, err
+		}
+		logger.Log.Info().Str("file_path", filePath).Msg("created a new encrypted file")
+		return true, nil
 	}
 	file, err := os.Create(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return false, fmt.Errorf("failed to create file: %w", err)
 	}
 	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		return false, fmt.Errorf("failed to write file content: %w", err)
+	}
 	logger.Log.Info().Str("file_path", filePath).Msg("created a new file")
+	return true, nil
+}
+
+// appendToFile appends content to the end of an existing file (decrypting and re-encrypting encrypted files)
+func appendToFile(filePath string, content string, key []byte) error {
+	if encryption.IsEncrypted(filePath) {
+		existing, err := encryption.ReadFile(key, filePath)
+		if err != nil {
+			return err
+		}
+		if err := encryption.WriteFile(key, filePath, append(existing, content...)); err != nil {
+			return err
+		}
+		logger.Log.Info().Str("file_path", filePath).Msg("appended a section to the encrypted file")
+		return nil
+	}
+	// #nosec G302: Expect file permissions to be 0600 or less
+	// The file already exists, so its permissions are left as they are
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file for appending: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("failed to append to file: %w", err)
+	}
+	logger.Log.Info().Str("file_path", filePath).Msg("appended a section to the file")
+	return nil
+}
+
+// nextFreePath returns the first path that does not exist of the form name-2.ext, name-3.ext, ...
+// (name-2.md.enc, ... for encrypted files)
+func nextFreePath(filePath string) (string, error) {
+	ext := encryption.FileExt(filePath)
+	stem := strings.TrimSuffix(filePath, ext)
+	for i := 2; i <= maxSuffix; i++ {
+		candidate := fmt.Sprintf("%s-%d%s", stem, i, ext)
+		if _, err := os.Stat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no free file name found for %s", filePath)
+}
+
+// backupFile moves an existing file out of the way (to name.ext.bak, or name.ext-2.bak, ... if taken)
+func backupFile(filePath string) error {
+	backupPath := filePath + ".bak"
+	if _, err := os.Stat(backupPath); err == nil {
+		if backupPath, err = nextFreePath(backupPath); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(filePath, backupPath); err != nil {
+		return fmt.Errorf("failed to back up file: %w", err)
+	}
+	logger.Log.Info().Str("file_path", filePath).
+		Str("backup_path", backupPath).
+		Msg("backed up existing file")
 	return nil
 }
 
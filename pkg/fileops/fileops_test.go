@@ -8,6 +8,7 @@ import (
 
 	app "github.com/matthewchivers/journal/pkg/application"
 	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/encryption"
 	"github.com/matthewchivers/journal/pkg/logger"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -29,6 +30,7 @@ func TestCreateNewFile(t *testing.T) {
 	tests := []struct {
 		name             string
 		cfg              *config.Config
+		content          string
 		expectedFilePath string
 		expectedError    bool
 		expectedErrorMsg string
@@ -103,6 +105,25 @@ func TestCreateNewFile(t *testing.T) {
 			expectedFilePath: filepath.Join(tempdir, time.Now().Format("2006/01/02")+"/foos/foo.md"),
 			expectedError:    false,
 		},
+		{
+			name: "successful file creation - with content",
+			cfg: &config.Config{
+				Paths: config.Paths{
+					BaseDirectory: tempdir,
+				},
+				Entries: []config.Entry{
+					{
+						ID:               "bar",
+						FileNamePattern:  "{{.EntryID}}.{{.FileExtension}}",
+						DirectoryPattern: "{{.Year.Num}}/{{.Month.Pad}}/{{.Day.Pad}}",
+						FileExtension:    "md",
+					},
+				},
+			},
+			content:          "# Standup\n\n## Yesterday\n",
+			expectedFilePath: filepath.Join(tempdir, time.Now().Format("2006/01/02"), "bar.md"),
+			expectedError:    false,
+		},
 	}
 
 	defer func() {
@@ -148,16 +169,188 @@ func TestCreateNewFile(t *testing.T) {
 			assert.Equal(t, tt.expectedFilePath, path)
 
 			// Main function under test
-			err = CreateNewFile(path)
+			_, err = CreateNewFile(path, tt.content)
 			// Assert error handling
 			if tt.expectedError {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
 			} else {
 				assert.NoError(t, err)
-				_, err := os.Stat(path)
+				content, err := os.ReadFile(path)
 				assert.NoError(t, err, "file should have been created successfully")
+				assert.Equal(t, tt.content, string(content))
 			}
 		})
 	}
 }
+
+// TestCreateFile tests the onExists policies applied by CreateFile when the file already exists
+func TestCreateFile(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	tests := []struct {
+		name         string
+		opts         ExistsOptions
+		existing     []string
+		wantFile     string
+		wantCreated  bool
+		wantContent  string
+		wantBackup   string
+		wantErr      bool
+		wantErrorMsg string
+	}{
+		{
+			name:        "file does not exist",
+			opts:        ExistsOptions{Policy: config.OnExistsError},
+			wantFile:    "note.md",
+			wantCreated: true,
+			wantContent: "# New",
+		},
+		{
+			name:        "open",
+			opts:        ExistsOptions{Policy: config.OnExistsOpen},
+			existing:    []string{"note.md"},
+			wantFile:    "note.md",
+			wantContent: "# Old",
+		},
+		{
+			name:        "default policy opens",
+			existing:    []string{"note.md"},
+			wantFile:    "note.md",
+			wantContent: "# Old",
+		},
+		{
+			name:         "error",
+			opts:         ExistsOptions{Policy: config.OnExistsError},
+			existing:     []string{"note.md"},
+			wantErr:      true,
+			wantErrorMsg: "file already exists",
+		},
+		{
+			name:        "append-section",
+			opts:        ExistsOptions{Policy: config.OnExistsAppendSection, Section: "\n## 09:30\n"},
+			existing:    []string{"note.md"},
+			wantFile:    "note.md",
+			wantContent: "# Old\n## 09:30\n",
+		},
+		{
+			name:        "suffix",
+			opts:        ExistsOptions{Policy: config.OnExistsSuffix},
+			existing:    []string{"note.md", "note-2.md"},
+			wantFile:    "note-3.md",
+			wantCreated: true,
+			wantContent: "# New",
+		},
+		{
+			name:        "overwrite",
+			opts:        ExistsOptions{Policy: config.OnExistsOverwrite},
+			existing:    []string{"note.md"},
+			wantFile:    "note.md",
+			wantContent: "# New",
+			wantBackup:  "note.md.bak",
+		},
+		{
+			name:        "overwrite - backup taken",
+			opts:        ExistsOptions{Policy: config.OnExistsOverwrite},
+			existing:    []string{"note.md", "note.md.bak"},
+			wantFile:    "note.md",
+			wantContent: "# New",
+			wantBackup:  "note.md-2.bak",
+		},
+		{
+			name:         "unknown policy",
+			opts:         ExistsOptions{Policy: "replace"},
+			existing:     []string{"note.md"},
+			wantErr:      true,
+			wantErrorMsg: "unknown onExists policy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.existing {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("# Old"), 0600))
+			}
+
+			filePath, created, err := CreateFile(filepath.Join(dir, "note.md"), "# New", tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrorMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tt.wantFile), filePath)
+			assert.Equal(t, tt.wantCreated, created)
+
+			content, err := os.ReadFile(filePath)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantContent, string(content))
+
+			if tt.wantBackup != "" {
+				backup, err := os.ReadFile(filepath.Join(dir, tt.wantBackup))
+				assert.NoError(t, err)
+				assert.Equal(t, "# Old", string(backup))
+			}
+		})
+	}
+}
+
+func TestCreateFileEncrypted(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+	key := make([]byte, encryption.KeySize)
+
+	tests := []struct {
+		name        string
+		opts        ExistsOptions
+		existing    bool
+		wantFile    string
+		wantCreated bool
+		wantContent string
+	}{
+		{
+			name:        "file does not exist",
+			wantFile:    "note.md.enc",
+			wantCreated: true,
+			wantContent: "# New",
+		},
+		{
+			name:        "append-section",
+			opts:        ExistsOptions{Policy: config.OnExistsAppendSection, Section: "\n## 09:30\n"},
+			existing:    true,
+			wantFile:    "note.md.enc",
+			wantContent: "# Old\n## 09:30\n",
+		},
+		{
+			name:        "suffix",
+			opts:        ExistsOptions{Policy: config.OnExistsSuffix},
+			existing:    true,
+			wantFile:    "note-2.md.enc",
+			wantCreated: true,
+			wantContent: "# New",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filePath := filepath.Join(dir, "note.md.enc")
+			if tt.existing {
+				assert.NoError(t, encryption.WriteFile(key, filePath, []byte("# Old")))
+			}
+			tt.opts.Key = key
+
+			gotPath, created, err := CreateFile(filePath, "# New", tt.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tt.wantFile), gotPath)
+			assert.Equal(t, tt.wantCreated, created)
+
+			raw, err := os.ReadFile(gotPath)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(raw), "# ")
+			content, err := encryption.ReadFile(key, gotPath)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantContent, string(content))
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package frontmatter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// delimiter is the line that opens and closes front matter
+const delimiter = "---"
+
+// ErrUnterminated is returned when content opens front matter without closing it
+var ErrUnterminated = errors.New("front matter is not terminated")
+
+// Fields are the fields of front matter, in the order they appear
+type Fields yaml.MapSlice
+
+// Split splits content into its front matter (the YAML between the opening and closing "---" lines)
+// and its body
+// Returns false if the content does not start with front matter
+func Split(content string) (string, string, bool, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if strings.TrimRight(lines[0], "\r\n") != delimiter {
+		return "", content, false, nil
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r\n") == delimiter {
+			return strings.Join(lines[1:i], ""), strings.Join(lines[i+1:], ""), true, nil
+		}
+	}
+	return "", content, false, ErrUnterminated
+}
+
+// Body returns the body of content, without its front matter
+// Content without (or with unterminated) front matter is returned as it is
+func Body(content string) string {
+	_, body, _, _ := Split(content)
+	return body
+}
+
+// Parse parses the front matter of content, returning its fields and the body
+// Content without front matter has no fields, and the whole content is the body
+func Parse(content string) (Fields, string, error) {
+	frontMatter, body, ok, err := Split(content)
+	if err != nil || !ok {
+		return nil, body, err
+	}
+	var fields yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(frontMatter), &fields); err != nil {
+		return nil, content, fmt.Errorf("invalid front matter: %w", err)
+	}
+	return Fields(fields), body, nil
+}
+
+// Render renders fields as front matter (including the "---" delimiters)
+// No front matter is rendered for empty fields
+func Render(fields Fields) (string, error) {
+	if len(fields) == 0 {
+		return "", nil
+	}
+	data, err := yaml.Marshal(yaml.MapSlice(fields))
+	if err != nil {
+		return "", fmt.Errorf("failed to render front matter: %w", err)
+	}
+	return delimiter + "\n" + string(data) + delimiter + "\n", nil
+}
+
+// SetField sets the value of an existing top-level field in the front matter of content
+// The rest of the content is left exactly as it is (comments, formatting and key order are kept)
+// Returns false if the content has no front matter, or the front matter has no such field
+func SetField(content string, key string, value string) (string, bool) {
+	lines := strings.SplitAfter(content, "\n")
+	if strings.TrimRight(lines[0], "\r\n") != delimiter {
+		return content, false
+	}
+	rendered, err := yaml.Marshal(yaml.MapSlice{{Key: key, Value: value}})
+	if err != nil {
+		return content, false
+	}
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r\n")
+		if line == delimiter {
+			break
+		}
+		if !strings.HasPrefix(line, key+":") {
+			continue
+		}
+		ending := lines[i][len(line):]
+		lines[i] = strings.TrimRight(string(rendered), "\n") + ending
+		return strings.Join(lines, ""), true
+	}
+	return content, false
+}
+
+// Get returns the value of a field
+func (f Fields) Get(key string) (interface{}, bool) {
+	for _, item := range f {
+		if k, ok := item.Key.(string); ok && k == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+// String returns the value of a field as a string (empty if the field is not set)
+func (f Fields) String(key string) string {
+	value, ok := f.Get(key)
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// Strings returns the value of a field as a list of strings
+// The field may be a YAML list, or a single comma separated string (e.g. "work, planning")
+func (f Fields) Strings(key string) []string {
+	value, ok := f.Get(key)
+	if !ok || value == nil {
+		return nil
+	}
+	values := []string{}
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			values = append(values, strings.TrimSpace(fmt.Sprint(item)))
+		}
+	default:
+		values = strings.Split(fmt.Sprint(v), ",")
+	}
+	result := []string{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// Time returns the value of a field as a time (RFC 3339, or a YYYY-MM-DD date in the given location)
+func (f Fields) Time(key string, loc *time.Location) (time.Time, bool) {
+	value, ok := f.Get(key)
+	if !ok {
+		return time.Time{}, false
+	}
+	if t, ok := value.(time.Time); ok {
+		return t, true
+	}
+	text := f.String(key)
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, text, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,153 @@
+package frontmatter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantFields Fields
+		wantBody   string
+		wantErr    bool
+	}{
+		{
+			name:    "front matter",
+			content: "---\ndate: 2024-08-02\nentryId: standup\ntags: [work, planning]\n---\n# Stand-up\n",
+			wantFields: Fields{
+				{Key: "date", Value: "2024-08-02"},
+				{Key: "entryId", Value: "standup"},
+				{Key: "tags", Value: []interface{}{"work", "planning"}},
+			},
+			wantBody: "# Stand-up\n",
+		},
+		{
+			name:       "windows line endings",
+			content:    "---\r\ntopic: planning\r\n---\r\nbody",
+			wantFields: Fields{{Key: "topic", Value: "planning"}},
+			wantBody:   "body",
+		},
+		{
+			name:     "no front matter",
+			content:  "# Stand-up\n---\n",
+			wantBody: "# Stand-up\n---\n",
+		},
+		{
+			name:    "unterminated",
+			content: "---\ndate: 2024-08-02\n# Stand-up\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid yaml",
+			content: "---\ndate: [2024\n---\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, body, err := Parse(tt.content)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFields, fields)
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestBody(t *testing.T) {
+	assert.Equal(t, "# Stand-up\n", Body("---\ndate: 2024-08-02\n---\n# Stand-up\n"))
+	assert.Equal(t, "# Stand-up\n---\n", Body("# Stand-up\n---\n"))
+	assert.Equal(t, "---\ndate: 2024-08-02\n", Body("---\ndate: 2024-08-02\n"), "unterminated front matter is kept")
+}
+
+func TestRender(t *testing.T) {
+	rendered, err := Render(Fields{
+		{Key: "date", Value: "2024-08-02"},
+		{Key: "topic", Value: "Q3: planning"},
+		{Key: "tags", Value: []string{"work", "planning"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "---\ndate: \"2024-08-02\"\ntopic: 'Q3: planning'\ntags:\n- work\n- planning\n---\n", rendered)
+
+	fields, body, err := Parse(rendered + "body")
+	assert.NoError(t, err)
+	assert.Equal(t, "Q3: planning", fields.String("topic"))
+	assert.Equal(t, "body", body)
+
+	rendered, err = Render(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, rendered)
+}
+
+func TestSetField(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantOk  bool
+	}{
+		{
+			name:    "existing field",
+			content: "---\ntitle: Standup # keep me\nmodified: \n---\n# Standup\n",
+			want:    "---\ntitle: Standup # keep me\nmodified: \"2024-08-02T09:30:00Z\"\n---\n# Standup\n",
+			wantOk:  true,
+		},
+		{
+			name:    "windows line endings",
+			content: "---\r\nmodified: 2024-08-01T10:00:00Z\r\n---\r\n",
+			want:    "---\r\nmodified: \"2024-08-02T09:30:00Z\"\r\n---\r\n",
+			wantOk:  true,
+		},
+		{
+			name:    "no such field",
+			content: "---\ntitle: Standup\n---\nmodified: in body\n",
+			want:    "---\ntitle: Standup\n---\nmodified: in body\n",
+		},
+		{
+			name:    "no front matter",
+			content: "# Standup\nmodified: in body\n",
+			want:    "# Standup\nmodified: in body\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SetField(tt.content, "modified", "2024-08-02T09:30:00Z")
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFieldAccessors(t *testing.T) {
+	var fields Fields
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+date: 2024-08-02
+modified: 2024-08-02T09:30:00+01:00
+tags: [work, " planning "]
+categories: "work, planning,"
+count: 3
+`), (*yaml.MapSlice)(&fields)))
+
+	assert.Equal(t, "3", fields.String("count"))
+	assert.Equal(t, "", fields.String("missing"))
+	assert.Equal(t, []string{"work", "planning"}, fields.Strings("tags"))
+	assert.Equal(t, []string{"work", "planning"}, fields.Strings("categories"))
+	assert.Nil(t, fields.Strings("missing"))
+
+	date, ok := fields.Time("date", time.UTC)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC), date)
+	modified, ok := fields.Time("modified", time.UTC)
+	assert.True(t, ok)
+	assert.True(t, modified.Equal(time.Date(2024, time.August, 2, 8, 30, 0, 0, time.UTC)))
+	_, ok = fields.Time("count", time.UTC)
+	assert.False(t, ok)
+}
@@ -0,0 +1,149 @@
+package gitops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+)
+
+// ErrNotRepository is returned when a directory is not within a git repository
+var ErrNotRepository = errors.New("not a git repository")
+
+// Repo is a git repository, operated with the git command line
+type Repo struct {
+	// Dir is the top-level directory of the repository's working tree
+	Dir string
+}
+
+// Open opens the git repository containing dir
+func Open(dir string) (*Repo, error) {
+	repo := &Repo{Dir: dir}
+	topLevel, err := repo.git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotRepository, dir)
+	}
+	repo.Dir = topLevel
+	return repo, nil
+}
+
+// Commit stages the changes to the given paths (files or directories, including removed files) and commits them,
+// leaving any other staged changes uncommitted
+// Returns false if there was nothing to commit
+func (r *Repo) Commit(message string, paths ...string) (bool, error) {
+	pathspec := []string{}
+	for _, path := range paths {
+		if r.known(path) {
+			pathspec = append(pathspec, path)
+		}
+	}
+	if len(pathspec) == 0 {
+		return false, nil
+	}
+	if _, err := r.git(append([]string{"add", "--all", "--"}, pathspec...)...); err != nil {
+		return false, fmt.Errorf("failed to stage changes: %w", err)
+	}
+	changed, err := r.hasStagedChanges(pathspec)
+	if err != nil || !changed {
+		return false, err
+	}
+	if _, err := r.git(append([]string{"commit", "--quiet", "--message", message, "--"}, pathspec...)...); err != nil {
+		return false, fmt.Errorf("failed to commit changes: %w", err)
+	}
+	logger.Log.Info().Str("repository", r.Dir).
+		Strs("paths", pathspec).
+		Str("commit_message", message).
+		Msg("committed changes")
+	return true, nil
+}
+
+// known returns true if the path exists, or is tracked by the repository (so its removal can be committed)
+func (r *Repo) known(path string) bool {
+	if _, err := os.Stat(path); err == nil {
+		return true
+	}
+	_, err := r.git("ls-files", "--error-unmatch", "--", path)
+	return err == nil
+}
+
+// hasStagedChanges returns true if there are staged changes to the paths
+func (r *Repo) hasStagedChanges(pathspec []string) (bool, error) {
+	_, err := r.git(append([]string{"diff", "--cached", "--quiet", "--"}, pathspec...)...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return true, nil
+	}
+	return false, err
+}
+
+// CurrentBranch returns the name of the branch checked out in the repository
+func (r *Repo) CurrentBranch() (string, error) {
+	branch, err := r.git("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to find the current branch: %w", err)
+	}
+	return branch, nil
+}
+
+// Sync pulls the branch from the remote (a remote name, URL or repository path), rebasing local commits onto it,
+// then pushes the branch to the remote
+// If the rebase fails (e.g. because of conflicting changes), it is aborted, leaving the local branch unchanged
+func (r *Repo) Sync(remote string, branch string) error {
+	exists, err := r.remoteHasBranch(remote, branch)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if _, err := r.git("pull", "--quiet", "--rebase", "--autostash", remote, branch); err != nil {
+			if _, abortErr := r.git("rebase", "--abort"); abortErr != nil {
+				logger.Log.Debug().Err(abortErr).Msg("no rebase to abort")
+			}
+			return fmt.Errorf("failed to pull from %s (resolve any conflicts with 'git pull --rebase' in %s): %w",
+				remote, r.Dir, err)
+		}
+		logger.Log.Info().Str("remote", remote).Str("branch", branch).Msg("pulled changes")
+	}
+	if _, err := r.git("push", "--quiet", remote, "HEAD:refs/heads/"+branch); err != nil {
+		return fmt.Errorf("failed to push to %s: %w", remote, err)
+	}
+	logger.Log.Info().Str("remote", remote).Str("branch", branch).Msg("pushed changes")
+	return nil
+}
+
+// remoteHasBranch returns true if the branch exists on the remote (it does not for a new, empty remote)
+func (r *Repo) remoteHasBranch(remote string, branch string) (bool, error) {
+	_, err := r.git("ls-remote", "--exit-code", "--heads", remote, "refs/heads/"+branch)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to query remote %s: %w", remote, err)
+	}
+	return true, nil
+}
+
+// git runs a git command in the repository, returning its (trimmed) output
+// Errors include the command's error output
+func (r *Repo) git(args ...string) (string, error) {
+	args = append([]string{"-C", filepath.Clean(r.Dir)}, args...)
+	// #nosec G204: Subprocess launched with a potential tainted input or cmd arguments
+	// The arguments are paths and settings from the configuration, and no shell is involved
+	cmd := exec.Command("git", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	logger.Log.Debug().Strs("args", args).Msg("running git")
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s", err, message)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
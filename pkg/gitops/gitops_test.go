@@ -0,0 +1,144 @@
+package gitops
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupGit isolates git from the user's configuration, and skips the test if git is not installed
+func setupGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+}
+
+// initRepo creates a repository (on branch main) in a new directory
+func initRepo(t *testing.T, args ...string) string {
+	dir := t.TempDir()
+	runGit(t, dir, append([]string{"init", "--quiet", "--initial-branch=main"}, args...)...)
+	return dir
+}
+
+// runGit runs a git command in dir, failing the test if it fails
+func runGit(t *testing.T, dir string, args ...string) string {
+	repo := &Repo{Dir: dir}
+	out, err := repo.git(args...)
+	assert.NoError(t, err)
+	return out
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func TestOpen(t *testing.T) {
+	setupGit(t)
+	dir := initRepo(t)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "journal"), 0755))
+
+	repo, err := Open(filepath.Join(dir, "journal"))
+	assert.NoError(t, err)
+	want, err := filepath.EvalSymlinks(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, want, repo.Dir)
+
+	_, err = Open(t.TempDir())
+	assert.ErrorIs(t, err, ErrNotRepository)
+}
+
+func TestCommit(t *testing.T) {
+	setupGit(t)
+	dir := initRepo(t)
+	repo, err := Open(dir)
+	assert.NoError(t, err)
+
+	entry := filepath.Join(dir, "journal", "2024", "standup.md")
+	other := filepath.Join(dir, "other.txt")
+	writeFile(t, entry, "# Stand-up\n")
+	writeFile(t, other, "unrelated\n")
+	runGit(t, dir, "add", other)
+
+	committed, err := repo.Commit("Add standup", entry)
+	assert.NoError(t, err)
+	assert.True(t, committed)
+	assert.Equal(t, "Add standup", runGit(t, dir, "log", "-1", "--format=%s"))
+	assert.Equal(t, "journal/2024/standup.md", runGit(t, dir, "show", "--name-only", "--format=", "HEAD"))
+	// Other staged changes are left staged, but uncommitted
+	assert.Equal(t, "other.txt", runGit(t, dir, "diff", "--cached", "--name-only"))
+
+	committed, err = repo.Commit("Nothing changed", entry)
+	assert.NoError(t, err)
+	assert.False(t, committed)
+
+	committed, err = repo.Commit("Never existed", filepath.Join(dir, "missing.md"))
+	assert.NoError(t, err)
+	assert.False(t, committed)
+
+	assert.NoError(t, os.Remove(entry))
+	writeFile(t, filepath.Join(dir, "journal", "review.md"), "# Review\n")
+	committed, err = repo.Commit("Update journal", filepath.Join(dir, "journal"))
+	assert.NoError(t, err)
+	assert.True(t, committed)
+	assert.Equal(t, "D\tjournal/2024/standup.md\nA\tjournal/review.md",
+		runGit(t, dir, "show", "--name-status", "--format=", "HEAD"))
+}
+
+func TestSync(t *testing.T) {
+	setupGit(t)
+	remote := initRepo(t, "--bare")
+
+	first := initRepo(t)
+	firstRepo, err := Open(first)
+	assert.NoError(t, err)
+	writeFile(t, filepath.Join(first, "a.md"), "a\n")
+	_, err = firstRepo.Commit("Add a", filepath.Join(first, "a.md"))
+	assert.NoError(t, err)
+	// The remote has no branches yet, so there is nothing to pull
+	assert.NoError(t, firstRepo.Sync(remote, "main"))
+
+	second := t.TempDir()
+	runGit(t, second, "clone", "--quiet", remote, ".")
+	secondRepo, err := Open(second)
+	assert.NoError(t, err)
+	writeFile(t, filepath.Join(second, "b.md"), "b\n")
+	_, err = secondRepo.Commit("Add b", filepath.Join(second, "b.md"))
+	assert.NoError(t, err)
+	assert.NoError(t, secondRepo.Sync(remote, "main"))
+
+	// Local commits are rebased onto the changes from the remote
+	writeFile(t, filepath.Join(first, "c.md"), "c\n")
+	_, err = firstRepo.Commit("Add c", filepath.Join(first, "c.md"))
+	assert.NoError(t, err)
+	assert.NoError(t, firstRepo.Sync(remote, "main"))
+	assert.Equal(t, "Add c\nAdd b\nAdd a", runGit(t, remote, "log", "--format=%s", "main"))
+	assert.FileExists(t, filepath.Join(first, "b.md"))
+
+	// Conflicting changes abort the rebase, leaving the local branch unchanged
+	writeFile(t, filepath.Join(second, "b.md"), "b from second\n")
+	_, err = secondRepo.Commit("Edit b", filepath.Join(second, "b.md"))
+	assert.NoError(t, err)
+	writeFile(t, filepath.Join(first, "b.md"), "b from first\n")
+	_, err = firstRepo.Commit("Edit b differently", filepath.Join(first, "b.md"))
+	assert.NoError(t, err)
+	assert.NoError(t, firstRepo.Sync(remote, "main"))
+	assert.Error(t, secondRepo.Sync(remote, "main"))
+	assert.Equal(t, "Edit b", runGit(t, second, "log", "-1", "--format=%s"))
+	branch, err := secondRepo.CurrentBranch()
+	assert.NoError(t, err)
+	assert.Equal(t, "main", branch)
+}
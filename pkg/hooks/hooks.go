@@ -0,0 +1,145 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/templating"
+)
+
+// Event is the point in the creation of an entry at which hooks run
+type Event string
+
+const (
+	// PreCreate hooks run before the entry's file is created
+	PreCreate Event = "preCreate"
+
+	// PostCreate hooks run after the entry's file is created
+	PostCreate Event = "postCreate"
+
+	// PostEdit hooks run after the editor has closed the entry's file
+	PostEdit Event = "postEdit"
+)
+
+// Payload describes the entry a hook runs for (given to hooks as JSON on stdin)
+type Payload struct {
+	// Event is the event the hook runs for
+	Event Event `json:"event"`
+
+	// Path is the full path to the entry's file
+	Path string `json:"path"`
+
+	// EntryID is the ID of the entry type
+	EntryID string `json:"entryId"`
+
+	// Topic is the topic of the entry
+	Topic string `json:"topic,omitempty"`
+
+	// Tags are the tags of the entry
+	Tags []string `json:"tags,omitempty"`
+
+	// Date is the date of the entry (YYYY-MM-DD)
+	Date string `json:"date"`
+
+	// Created is true if the file was created (rather than already existing) - false for preCreate hooks
+	Created bool `json:"created"`
+
+	// Fields are the template fields of the entry, as used in patterns (e.g. Fields.Year.Num for {{.Year.Num}})
+	Fields templating.TemplateModel `json:"fields"`
+}
+
+// Runner runs hook commands
+type Runner struct {
+	// Stdout and Stderr receive the output of the commands
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// NewPayload creates the payload for an entry from its template fields
+func NewPayload(event Event, filePath string, data templating.TemplateModel, created bool) Payload {
+	return Payload{
+		Event:   event,
+		Path:    filePath,
+		EntryID: data.EntryID,
+		Topic:   data.Topic,
+		Tags:    data.Tags,
+		Date:    data.Year.Num + "-" + data.Month.Pad + "-" + data.Day.Pad,
+		Created: created,
+		Fields:  data,
+	}
+}
+
+// Run runs the commands in order with the shell, each given the payload as JSON on stdin and in environment
+// variables (see Environ)
+// Stops at, and returns the error of, the first command that fails (e.g. exits with a non-zero status)
+func (r Runner) Run(commands []string, payload Payload) error {
+	if len(commands) == 0 {
+		return nil
+	}
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to describe entry for hooks: %w", err)
+	}
+	env := append(os.Environ(), Environ(payload)...)
+	for _, command := range commands {
+		logger.Log.Info().Str("event", string(payload.Event)).
+			Str("command", command).
+			Str("file_path", payload.Path).
+			Msg("running hook")
+		cmd := shellCommand(command)
+		cmd.Stdin = bytes.NewReader(input)
+		cmd.Stdout = r.Stdout
+		cmd.Stderr = r.Stderr
+		cmd.Env = env
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", payload.Event, command, err)
+		}
+	}
+	return nil
+}
+
+// Environ returns the environment variables describing the entry to hooks
+func Environ(payload Payload) []string {
+	data := payload.Fields
+	vars := [][2]string{
+		{"JOURNAL_EVENT", string(payload.Event)},
+		{"JOURNAL_PATH", payload.Path},
+		{"JOURNAL_ENTRY_ID", payload.EntryID},
+		{"JOURNAL_TOPIC", payload.Topic},
+		{"JOURNAL_TAGS", strings.Join(payload.Tags, ",")},
+		{"JOURNAL_DATE", payload.Date},
+		{"JOURNAL_CREATED", strconv.FormatBool(payload.Created)},
+		{"JOURNAL_YEAR", data.Year.Num},
+		{"JOURNAL_MONTH", data.Month.Pad},
+		{"JOURNAL_MONTH_NAME", data.Month.Name},
+		{"JOURNAL_DAY", data.Day.Pad},
+		{"JOURNAL_WEEKDAY", data.Day.Name},
+		{"JOURNAL_WEEK", data.Year.Week.Pad},
+		{"JOURNAL_WKCOM", data.WkCom.Year.Num + "-" + data.WkCom.Month.Pad + "-" + data.WkCom.Day.Pad},
+	}
+	env := []string{}
+	for _, v := range vars {
+		env = append(env, v[0]+"="+v[1])
+	}
+	return env
+}
+
+// shellCommand creates the command to run a hook with the shell (sh, or cmd on Windows)
+func shellCommand(command string) *exec.Cmd {
+	// #nosec G204: Subprocess launched with a potential tainted input or cmd arguments
+	// Hooks are commands configured by the user, to be run by their shell
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	// #nosec G204: Subprocess launched with a potential tainted input or cmd arguments
+	// Hooks are commands configured by the user, to be run by their shell
+	return exec.Command("sh", "-c", command)
+}
@@ -0,0 +1,88 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/templating"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPayload(t *testing.T, event Event) Payload {
+	data, err := templating.PrepareTemplateData(time.Date(2024, time.August, 2, 9, 30, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	data.EntryID = "meeting"
+	data.Topic = "planning"
+	data.Tags = []string{"work", "client-x"}
+	return NewPayload(event, "/journal/meetings/2024-08-02.md", data, true)
+}
+
+func TestEnviron(t *testing.T) {
+	assert.Equal(t, []string{
+		"JOURNAL_EVENT=postCreate",
+		"JOURNAL_PATH=/journal/meetings/2024-08-02.md",
+		"JOURNAL_ENTRY_ID=meeting",
+		"JOURNAL_TOPIC=planning",
+		"JOURNAL_TAGS=work,client-x",
+		"JOURNAL_DATE=2024-08-02",
+		"JOURNAL_CREATED=true",
+		"JOURNAL_YEAR=2024",
+		"JOURNAL_MONTH=08",
+		"JOURNAL_MONTH_NAME=August",
+		"JOURNAL_DAY=02",
+		"JOURNAL_WEEKDAY=Friday",
+		"JOURNAL_WEEK=31",
+		"JOURNAL_WKCOM=2024-07-29",
+	}, Environ(testPayload(t, PostCreate)))
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.json")
+	marker := filepath.Join(dir, "marker")
+	var stdout, stderr bytes.Buffer
+	runner := Runner{Stdout: &stdout, Stderr: &stderr}
+	payload := testPayload(t, PreCreate)
+
+	err := runner.Run([]string{
+		"cat > " + input,
+		`echo "$JOURNAL_ENTRY_ID $JOURNAL_DATE ($JOURNAL_TOPIC)"`,
+	}, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "meeting 2024-08-02 (planning)\n", stdout.String())
+
+	content, err := os.ReadFile(input)
+	assert.NoError(t, err)
+	var got map[string]interface{}
+	assert.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, "preCreate", got["event"])
+	assert.Equal(t, "/journal/meetings/2024-08-02.md", got["path"])
+	assert.Equal(t, "meeting", got["entryId"])
+	assert.Equal(t, []interface{}{"work", "client-x"}, got["tags"])
+	assert.Equal(t, "2024-08-02", got["date"])
+	fields := got["fields"].(map[string]interface{})
+	assert.Equal(t, "Friday", fields["Day"].(map[string]interface{})["Name"])
+
+	// The first failing command stops the hooks
+	err = runner.Run([]string{"echo failed >&2; exit 3", "touch " + marker}, payload)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "preCreate hook")
+	assert.Contains(t, err.Error(), "exit status 3")
+	assert.Equal(t, "failed\n", stderr.String())
+	assert.NoFileExists(t, marker)
+
+	assert.NoError(t, runner.Run(nil, payload))
+}
@@ -0,0 +1,266 @@
+package index
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/frontmatter"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/matthewchivers/journal/pkg/tags"
+)
+
+// Version is the version of the index file format
+// Indexes written with a different version are rebuilt rather than read
+const Version = 4
+
+// ErrStale is returned by Load when the index was written by a different version, or for a different
+// configuration (entry patterns, base directories or timezone), and so must be rebuilt
+var ErrStale = errors.New("index is stale")
+
+// Entry is the indexed information about an entry file
+type Entry struct {
+	catalog.Record
+
+	// WordCount is the number of words in the entry
+	WordCount int `json:"wordCount"`
+
+	// ModTime is the modification time of the file when it was indexed
+	ModTime time.Time `json:"modTime"`
+
+	// Hash is the SHA-256 hash of the content of the file (hex encoded)
+	Hash string `json:"hash"`
+}
+
+// Index is an on-disk index of the entries in the journal
+// It is used to answer queries (e.g. list, search) without walking every directory
+type Index struct {
+	// Version is the version of the index file format
+	Version int `json:"version"`
+
+	// Fingerprint identifies the configuration the index was built for
+	Fingerprint string `json:"fingerprint"`
+
+	// Entries are the indexed entries, sorted by date, then path
+	Entries []Entry `json:"entries"`
+}
+
+// Fingerprint returns a fingerprint of the parts of the configuration that determine the contents of
+// the index: the base directories and patterns of each entry, and the timezone dates are recovered in
+func Fingerprint(cfg *config.Config, loc *time.Location) (string, error) {
+	type entryFingerprint struct {
+		ID, BaseDirectory, DirectoryPattern, FileNamePattern string
+	}
+	fingerprint := struct {
+		BaseDirectory string
+		Location      string
+		Entries       []entryFingerprint
+	}{
+		BaseDirectory: cfg.Paths.BaseDirectory,
+		Location:      loc.String(),
+	}
+	for _, entry := range cfg.Entries {
+		fingerprint.Entries = append(fingerprint.Entries, entryFingerprint{
+			ID:               entry.ID,
+			BaseDirectory:    entry.BaseDirectory,
+			DirectoryPattern: entry.DirectoryPattern,
+			FileNamePattern:  entry.FileNamePattern,
+		})
+	}
+	data, err := json.Marshal(fingerprint)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Build creates a new index by scanning the journal
+func Build(cfg *config.Config, loc *time.Location) (*Index, error) {
+	fingerprint, err := Fingerprint(cfg, loc)
+	if err != nil {
+		return nil, err
+	}
+	records, err := catalog.Scan(cfg, loc)
+	if err != nil {
+		return nil, err
+	}
+	ix := &Index{Version: Version, Fingerprint: fingerprint, Entries: []Entry{}}
+	for _, record := range records {
+		entry, err := Describe(record)
+		if err != nil {
+			logger.Log.Warn().Err(err).Str("file_path", record.Path).Msg("error indexing file")
+			continue
+		}
+		ix.Entries = append(ix.Entries, entry)
+	}
+	logger.Log.Info().Int("entries", len(ix.Entries)).Msg("index built")
+	return ix, nil
+}
+
+// Load reads the index at indexPath
+// Returns an error wrapping os.ErrNotExist if there is no index, or ErrStale if the index must be rebuilt
+// (for the given configuration and location)
+func Load(indexPath string, cfg *config.Config, loc *time.Location) (*Index, error) {
+	// #nosec G304: Potential file inclusion via variable
+	// The index path is set by the application (or the user's configuration)
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	ix := &Index{}
+	if err := json.Unmarshal(data, ix); err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	fingerprint, err := Fingerprint(cfg, loc)
+	if err != nil {
+		return nil, err
+	}
+	if ix.Version != Version || ix.Fingerprint != fingerprint {
+		return nil, ErrStale
+	}
+	return ix, nil
+}
+
+// Save writes the index to indexPath
+// The index is written to a temporary file first, so a partially written index is never read
+func (ix *Index) Save(indexPath string) error {
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+	tempPath := indexPath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tempPath, indexPath); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	logger.Log.Debug().Str("index_path", indexPath).
+		Int("entries", len(ix.Entries)).
+		Msg("index saved")
+	return nil
+}
+
+// Update (re)indexes the file of a record, adding it to the index if it is not already present
+func (ix *Index) Update(record catalog.Record) error {
+	entry, err := Describe(record)
+	if err != nil {
+		return err
+	}
+	ix.Remove(record.Path)
+	ix.Entries = append(ix.Entries, entry)
+	sort.SliceStable(ix.Entries, func(i, j int) bool {
+		if !ix.Entries[i].Date.Equal(ix.Entries[j].Date) {
+			return ix.Entries[i].Date.Before(ix.Entries[j].Date)
+		}
+		return ix.Entries[i].Path < ix.Entries[j].Path
+	})
+	return nil
+}
+
+// Refresh brings the index up to date with the journal: files that are new or have been modified since they
+// were indexed are (re)indexed, and files that no longer exist are removed
+// The journal is walked, but only new and modified files are read
+// Returns whether the index changed
+func (ix *Index) Refresh(cfg *config.Config, loc *time.Location) (bool, error) {
+	records, err := catalog.Scan(cfg, loc)
+	if err != nil {
+		return false, err
+	}
+	indexed := make(map[string]Entry, len(ix.Entries))
+	for _, entry := range ix.Entries {
+		indexed[entry.Path] = entry
+	}
+	changed := len(records) != len(ix.Entries)
+	entries := make([]Entry, 0, len(records))
+	for _, record := range records {
+		if entry, ok := indexed[record.Path]; ok && isUnmodified(entry) {
+			entries = append(entries, entry)
+			continue
+		}
+		changed = true
+		entry, err := Describe(record)
+		if err != nil {
+			logger.Log.Warn().Err(err).Str("file_path", record.Path).Msg("error indexing file")
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	// The records are sorted in the same order as the index
+	ix.Entries = entries
+	return changed, nil
+}
+
+// isUnmodified reports whether an entry's file has not been modified since it was indexed
+func isUnmodified(entry Entry) bool {
+	info, err := os.Stat(entry.Path)
+	return err == nil && info.ModTime().Equal(entry.ModTime)
+}
+
+// Remove removes the entry for a file from the index, returning false if it was not indexed
+func (ix *Index) Remove(filePath string) bool {
+	for i, entry := range ix.Entries {
+		if entry.Path == filePath {
+			ix.Entries = append(ix.Entries[:i], ix.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Records returns the catalog records of the indexed entries
+func (ix *Index) Records() []catalog.Record {
+	records := make([]catalog.Record, 0, len(ix.Entries))
+	for _, entry := range ix.Entries {
+		records = append(records, entry.Record)
+	}
+	return records
+}
+
+// Describe reads the file of a record and returns its index entry
+// Words are counted in the entry's body (without its front matter); the record's tags are read from the file (see tags.Extract), and if the entry's patterns do not contain
+// a topic, the topic is read from the file's front matter (if set)
+// Only the path, modification time and hash of encrypted files are indexed
+func Describe(record catalog.Record) (Entry, error) {
+	info, err := os.Stat(record.Path)
+	if err != nil {
+		return Entry{}, err
+	}
+	// #nosec G304: Potential file inclusion via variable
+	// The path was found by walking the configured base directories
+	content, err := os.ReadFile(record.Path)
+	if err != nil {
+		return Entry{}, err
+	}
+	sum := sha256.Sum256(content)
+	entry := Entry{
+		Record:  record,
+		ModTime: info.ModTime(),
+		Hash:    hex.EncodeToString(sum[:]),
+	}
+	// The content of encrypted files is not indexed, so that none of it is stored unencrypted
+	if record.Encrypted {
+		return entry, nil
+	}
+	if entry.Topic == "" {
+		if fields, _, err := frontmatter.Parse(string(content)); err == nil {
+			entry.Topic = fields.String("topic")
+		}
+	}
+	entry.Tags = tags.Extract(string(content))
+	entry.WordCount = len(strings.Fields(frontmatter.Body(string(content))))
+	return entry, nil
+}
@@ -0,0 +1,181 @@
+package index
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/matthewchivers/journal/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestJournal creates a journal with the given files (path relative to the base directory => content)
+func newTestJournal(t *testing.T, files map[string]string) *config.Config {
+	baseDir := t.TempDir()
+	for file, content := range files {
+		filePath := filepath.Join(baseDir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+	}
+	return &config.Config{
+		Paths: config.Paths{BaseDirectory: baseDir},
+		Entries: []config.Entry{
+			{
+				ID:               "meeting",
+				DirectoryPattern: "{{.EntryID}}s/{{.Topic}}",
+				FileNamePattern:  "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.{{.FileExtension}}",
+			},
+		},
+	}
+}
+
+func TestBuildSaveLoad(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	cfg := newTestJournal(t, map[string]string{
+		"meetings/planning/2024-08-01.md": "# Planning\n\nAgreed the #Roadmap for #q3 (see #roadmap)\n",
+		"meetings/retro/2024-08-02.md":    "# Retro\n",
+		"meetings/notes.txt":              "not an entry",
+	})
+
+	ix, err := Build(cfg, time.UTC)
+	assert.NoError(t, err)
+	assert.Len(t, ix.Entries, 2)
+
+	planning := ix.Entries[0]
+	assert.Equal(t, "meeting", planning.EntryID)
+	assert.Equal(t, "planning", planning.Topic)
+	assert.Equal(t, []string{"q3", "roadmap"}, planning.Tags)
+	assert.Equal(t, 9, planning.WordCount)
+	assert.Len(t, planning.Hash, 64)
+	assert.Nil(t, ix.Entries[1].Tags)
+
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	assert.NoError(t, ix.Save(indexPath))
+
+	loaded, err := Load(indexPath, cfg, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, ix.Fingerprint, loaded.Fingerprint)
+	assert.Len(t, loaded.Entries, 2)
+	assert.True(t, loaded.Entries[0].Date.Equal(time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, planning.Hash, loaded.Entries[0].Hash)
+
+	_, err = Load(indexPath, cfg, time.FixedZone("UTC+8", 8*60*60))
+	assert.ErrorIs(t, err, ErrStale, "a different timezone makes the index stale")
+
+	cfg.Entries[0].FileNamePattern = "{{.Day.Pad}}.{{.FileExtension}}"
+	_, err = Load(indexPath, cfg, time.UTC)
+	assert.ErrorIs(t, err, ErrStale, "different patterns make the index stale")
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"), cfg, time.UTC)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestDescribeFrontMatter(t *testing.T) {
+	cfg := newTestJournal(t, map[string]string{
+		"meetings/planning/2024-08-01.md": "---\ndate: 2024-08-01\ntopic: roadmap planning\n---\n# Planning\n",
+		"meetings/retro/2024-08-02.md":    "---\ndate: 2024-08-02\nauthor: Sam\n---\n",
+	})
+
+	planning, err := Describe(catalog.Record{Path: filepath.Join(cfg.Paths.BaseDirectory, "meetings/planning/2024-08-01.md")})
+	assert.NoError(t, err)
+	assert.Equal(t, "roadmap planning", planning.Topic)
+	assert.Equal(t, 2, planning.WordCount, "words in the front matter are not counted")
+
+	retro, err := Describe(catalog.Record{Path: filepath.Join(cfg.Paths.BaseDirectory, "meetings/retro/2024-08-02.md")})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, retro.WordCount)
+}
+
+func TestUpdateRemove(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	cfg := newTestJournal(t, map[string]string{
+		"meetings/retro/2024-08-02.md": "# Retro\n",
+	})
+	ix, err := Build(cfg, time.UTC)
+	assert.NoError(t, err)
+
+	planningPath := filepath.Join(cfg.Paths.BaseDirectory, "meetings/planning/2024-08-01.md")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(planningPath), 0755))
+	assert.NoError(t, os.WriteFile(planningPath, []byte("# Planning #roadmap"), 0644))
+	planning := catalog.Record{
+		Path:    planningPath,
+		EntryID: "meeting",
+		Date:    time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+		Topic:   "planning",
+	}
+	assert.NoError(t, ix.Update(planning))
+	assert.Len(t, ix.Entries, 2)
+	assert.Equal(t, planningPath, ix.Entries[0].Path, "entries are kept in date order")
+	assert.Equal(t, 3, ix.Entries[0].WordCount)
+
+	assert.NoError(t, os.WriteFile(planningPath, []byte("# Planning"), 0644))
+	assert.NoError(t, ix.Update(planning))
+	assert.Len(t, ix.Entries, 2, "updating an indexed file replaces its entry")
+	assert.Equal(t, 2, ix.Entries[0].WordCount)
+	assert.Nil(t, ix.Entries[0].Tags)
+
+	retroPath := filepath.Join(cfg.Paths.BaseDirectory, "meetings/retro/2024-08-02.md")
+	assert.NoError(t, os.WriteFile(retroPath, []byte("---\ntopic: sprint 12\n---\n# Retro\n"), 0644))
+	retro := catalog.Record{Path: retroPath, EntryID: "meeting", Date: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)}
+	assert.NoError(t, ix.Update(retro))
+	assert.Equal(t, "sprint 12", ix.Entries[1].Topic, "the topic is read from front matter if the path has none")
+
+	assert.True(t, ix.Remove(planningPath))
+	assert.False(t, ix.Remove(planningPath))
+	assert.Equal(t, []catalog.Record{ix.Entries[0].Record}, ix.Records())
+
+	err = ix.Update(catalog.Record{Path: filepath.Join(cfg.Paths.BaseDirectory, "missing.md")})
+	assert.Error(t, err)
+}
+
+func TestRefresh(t *testing.T) {
+	tempLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	logger.SetLogger(&tempLogger)
+
+	cfg := newTestJournal(t, map[string]string{
+		"meetings/planning/2024-08-01.md": "# Planning\n",
+		"meetings/retro/2024-08-02.md":    "# Retro\n",
+	})
+	ix, err := Build(cfg, time.UTC)
+	assert.NoError(t, err)
+
+	changed, err := ix.Refresh(cfg, time.UTC)
+	assert.NoError(t, err)
+	assert.False(t, changed, "an up to date index is unchanged")
+
+	planningPath := filepath.Join(cfg.Paths.BaseDirectory, "meetings/planning/2024-08-01.md")
+	assert.NoError(t, os.WriteFile(planningPath, []byte("# Planning #roadmap\n"), 0644))
+	modTime := time.Date(2024, time.August, 3, 9, 0, 0, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(planningPath, modTime, modTime))
+	assert.NoError(t, os.Remove(filepath.Join(cfg.Paths.BaseDirectory, "meetings/retro/2024-08-02.md")))
+	reviewPath := filepath.Join(cfg.Paths.BaseDirectory, "meetings/review/2024-08-05.md")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(reviewPath), 0755))
+	assert.NoError(t, os.WriteFile(reviewPath, []byte("# Review\n"), 0644))
+
+	changed, err = ix.Refresh(cfg, time.UTC)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Len(t, ix.Entries, 2, "removed files are dropped and new files are added")
+	assert.Equal(t, planningPath, ix.Entries[0].Path)
+	assert.Equal(t, []string{"roadmap"}, ix.Entries[0].Tags, "modified files are re-read")
+	assert.True(t, ix.Entries[0].ModTime.Equal(modTime))
+	assert.Equal(t, reviewPath, ix.Entries[1].Path)
+	assert.Equal(t, "review", ix.Entries[1].Topic)
+
+	// Unmodified files are not re-read, so content changed without changing the modification time is kept
+	assert.NoError(t, os.WriteFile(planningPath, []byte("# Planning\n"), 0644))
+	assert.NoError(t, os.Chtimes(planningPath, modTime, modTime))
+	changed, err = ix.Refresh(cfg, time.UTC)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, []string{"roadmap"}, ix.Entries[0].Tags)
+}
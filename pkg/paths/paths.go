@@ -3,6 +3,7 @@ package paths
 import (
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 var (
@@ -26,3 +27,15 @@ func GetAppHomePath() (string, error) {
 func SetAppHomePath(path string) {
 	appHomePath = path
 }
+
+// ExpandHome expands a leading "~" in the given path to the user's home directory
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
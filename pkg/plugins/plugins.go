@@ -0,0 +1,86 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix is the prefix of the names of plugin executables (e.g. journal-foo is run for 'journal foo')
+const Prefix = "journal-"
+
+// ErrNotFound is returned when there is no executable for a plugin
+var ErrNotFound = errors.New("plugin not found")
+
+// namePattern matches valid plugin names (which must not be paths, or look like flags)
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// Plugin is an external command, run as a journal subcommand
+type Plugin struct {
+	// Name is the name of the subcommand (e.g. foo)
+	Name string
+
+	// Path is the path to the plugin's executable (e.g. ~/.journal/plugins/journal-foo)
+	Path string
+}
+
+// Find finds the executable for the plugin with the given name: journal-<name> in the plugins directory,
+// or else on the PATH
+func Find(name string, pluginsDir string) (Plugin, error) {
+	if !namePattern.MatchString(name) {
+		return Plugin{}, fmt.Errorf("%w: invalid plugin name %q", ErrNotFound, name)
+	}
+	candidates := []string{}
+	if pluginsDir != "" {
+		candidates = append(candidates, filepath.Join(pluginsDir, Prefix+name))
+	}
+	// A name without a path separator is searched for on the PATH
+	candidates = append(candidates, Prefix+name)
+	for _, candidate := range candidates {
+		if path, err := exec.LookPath(candidate); err == nil {
+			return Plugin{Name: name, Path: path}, nil
+		}
+	}
+	return Plugin{}, fmt.Errorf("%w: %s", ErrNotFound, Prefix+name)
+}
+
+// List returns the plugins in the plugins directory and on the PATH, sorted by name
+// Where plugins have the same name, the one that Find would run is listed
+func List(pluginsDir string) []Plugin {
+	dirs := append([]string{pluginsDir}, filepath.SplitList(os.Getenv("PATH"))...)
+	found := map[string]bool{}
+	plugins := []Plugin{}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			name := strings.TrimPrefix(file.Name(), Prefix)
+			if runtime.GOOS == "windows" {
+				// Executables are found by their extension (e.g. journal-foo.exe)
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if !strings.HasPrefix(file.Name(), Prefix) || found[name] || !namePattern.MatchString(name) {
+				continue
+			}
+			path, err := exec.LookPath(filepath.Join(dir, file.Name()))
+			if err != nil {
+				continue
+			}
+			found[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
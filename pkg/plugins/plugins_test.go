@@ -0,0 +1,57 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeExecutable(t *testing.T, path string, mode os.FileMode) {
+	assert.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), mode))
+}
+
+func TestFind(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are found by their extension on Windows")
+	}
+	pluginsDir := t.TempDir()
+	pathDir := t.TempDir()
+	t.Setenv("PATH", pathDir)
+	writeExecutable(t, filepath.Join(pluginsDir, "journal-report"), 0700)
+	writeExecutable(t, filepath.Join(pathDir, "journal-report"), 0700)
+	writeExecutable(t, filepath.Join(pathDir, "journal-standup-bot"), 0700)
+	writeExecutable(t, filepath.Join(pathDir, "journal-notes"), 0600)
+	writeExecutable(t, filepath.Join(pathDir, "journal-notes.sh"), 0700)
+
+	tests := []struct {
+		name     string
+		plugin   string
+		wantPath string
+	}{
+		{name: "plugins directory first", plugin: "report", wantPath: filepath.Join(pluginsDir, "journal-report")},
+		{name: "on the PATH", plugin: "standup-bot", wantPath: filepath.Join(pathDir, "journal-standup-bot")},
+		{name: "not executable", plugin: "notes"},
+		{name: "missing", plugin: "missing"},
+		{name: "path", plugin: "../journal-report"},
+		{name: "flag", plugin: "-report"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin, err := Find(tt.plugin, pluginsDir)
+			if tt.wantPath == "" {
+				assert.ErrorIs(t, err, ErrNotFound)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, Plugin{Name: tt.plugin, Path: tt.wantPath}, plugin)
+		})
+	}
+
+	assert.Equal(t, []Plugin{
+		{Name: "report", Path: filepath.Join(pluginsDir, "journal-report")},
+		{Name: "standup-bot", Path: filepath.Join(pathDir, "journal-standup-bot")},
+	}, List(pluginsDir))
+}
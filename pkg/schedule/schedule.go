@@ -0,0 +1,155 @@
+package schedule
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/caltools"
+	"github.com/matthewchivers/journal/pkg/config"
+)
+
+// defaultStart is the anchor for intervals when a schedule does not specify a start date
+// (a Monday, so that weekly intervals line up with week commencing dates)
+var defaultStart = time.Date(1970, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+// IsDue reports whether an entry with the given schedule is due on the given date
+// Schedules without a frequency are never due
+func IsDue(schedule config.Schedule, date time.Time) (bool, error) {
+	if schedule.Frequency == "" {
+		return false, nil
+	}
+	date = caltools.StartOfDay(date)
+	start, err := startDate(schedule, date.Location())
+	if err != nil {
+		return false, err
+	}
+	if date.Before(start) {
+		return false, nil
+	}
+	if !matchesFilters(withDefaults(schedule, start), date) {
+		return false, nil
+	}
+	return onInterval(schedule, start, date)
+}
+
+// startDate returns the date the schedule starts from (in the given location)
+func startDate(schedule config.Schedule, loc *time.Location) (time.Time, error) {
+	if schedule.StartDate == "" {
+		return time.Date(defaultStart.Year(), defaultStart.Month(), defaultStart.Day(), 0, 0, 0, 0, loc), nil
+	}
+	start, err := time.ParseInLocation(time.DateOnly, schedule.StartDate, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start date: %w", err)
+	}
+	return start, nil
+}
+
+// withDefaults fills in the filters implied by the frequency when none are specified
+// e.g. a weekly schedule with no days is due on the weekday of its start date (or Monday if it has none)
+func withDefaults(schedule config.Schedule, start time.Time) config.Schedule {
+	weekday, date, month := 1, 1, 1
+	if schedule.StartDate != "" {
+		weekday, date, month = caltools.ISOWeekday(start.Weekday()), start.Day(), int(start.Month())
+	}
+	noDayFilters := len(schedule.Days) == 0 && len(schedule.Dates) == 0 && len(schedule.Weeks) == 0
+	switch schedule.Frequency {
+	case config.FrequencyWeekly:
+		if len(schedule.Days) == 0 {
+			schedule.Days = []int{weekday}
+		}
+	case config.FrequencyMonthly:
+		if noDayFilters {
+			schedule.Dates = []int{date}
+		}
+	case config.FrequencyYearly:
+		if len(schedule.Months) == 0 {
+			schedule.Months = []int{month}
+		}
+		if noDayFilters {
+			schedule.Dates = []int{date}
+		}
+	}
+	if len(schedule.Weeks) > 0 && len(schedule.Days) == 0 {
+		schedule.Days = []int{1}
+	}
+	return schedule
+}
+
+// matchesFilters reports whether the date satisfies every (non-empty) filter in the schedule
+func matchesFilters(schedule config.Schedule, date time.Time) bool {
+	if len(schedule.Days) > 0 && !slices.Contains(schedule.Days, caltools.ISOWeekday(date.Weekday())) {
+		return false
+	}
+	if len(schedule.Dates) > 0 && !matchesDates(schedule.Dates, date) {
+		return false
+	}
+	if len(schedule.Weeks) > 0 && !slices.Contains(schedule.Weeks, caltools.WeekOfMonth(date)) {
+		return false
+	}
+	if len(schedule.Months) > 0 && !slices.Contains(schedule.Months, int(date.Month())) {
+		return false
+	}
+	return true
+}
+
+// matchesDates reports whether the date falls on one of the given dates of the month
+// Dates beyond the end of a short month (e.g. the 31st in April) fall on the last day of that month
+func matchesDates(dates []int, date time.Time) bool {
+	daysInMonth := caltools.DaysInMonth(date)
+	for _, d := range dates {
+		if d == date.Day() || (d > daysInMonth && date.Day() == daysInMonth) {
+			return true
+		}
+	}
+	return false
+}
+
+// onInterval reports whether the date falls on an occurrence of the schedule's interval (counted from start)
+func onInterval(schedule config.Schedule, start time.Time, date time.Time) (bool, error) {
+	interval := schedule.Interval
+	if interval <= 1 {
+		return true, nil
+	}
+	var elapsed int
+	switch schedule.Frequency {
+	case config.FrequencyDaily:
+		elapsed = daysBetween(start, date)
+	case config.FrequencyWeekly:
+		elapsed = daysBetween(caltools.WeekCommencing(start), caltools.WeekCommencing(date)) / 7
+	case config.FrequencyMonthly:
+		elapsed = (date.Year()-start.Year())*12 + int(date.Month()) - int(start.Month())
+	case config.FrequencyYearly:
+		elapsed = date.Year() - start.Year()
+	default:
+		return false, fmt.Errorf("unknown frequency: %s", schedule.Frequency)
+	}
+	return elapsed%interval == 0, nil
+}
+
+// daysBetween returns the number of calendar days from start to end
+// (calculated in UTC so that daylight saving changes do not affect the result)
+func daysBetween(start time.Time, end time.Time) int {
+	startUTC := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endUTC := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	return int(endUTC.Sub(startUTC).Hours() / 24)
+}
+
+// Occurrences returns every date from "from" to "to" (inclusive) on which the schedule is due
+func Occurrences(schedule config.Schedule, from time.Time, to time.Time) ([]time.Time, error) {
+	from, to = caltools.StartOfDay(from), caltools.StartOfDay(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("end date %s is before start date %s", to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+	occurrences := []time.Time{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		due, err := IsDue(schedule, date)
+		if err != nil {
+			return nil, err
+		}
+		if due {
+			occurrences = append(occurrences, date)
+		}
+	}
+	return occurrences, nil
+}
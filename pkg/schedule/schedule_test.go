@@ -0,0 +1,229 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDue(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule config.Schedule
+		date     time.Time
+		want     bool
+		wantErr  bool
+	}{
+		{
+			name:     "no frequency",
+			schedule: config.Schedule{},
+			date:     time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "daily",
+			schedule: config.Schedule{Frequency: "daily"},
+			date:     time.Date(2024, time.August, 3, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "daily on weekdays - Friday",
+			schedule: config.Schedule{Frequency: "daily", Days: []int{1, 2, 3, 4, 5}},
+			date:     time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "daily on weekdays - Sunday",
+			schedule: config.Schedule{Frequency: "daily", Days: []int{1, 2, 3, 4, 5}},
+			date:     time.Date(2024, time.August, 4, 0, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "every other day - on interval",
+			schedule: config.Schedule{Frequency: "daily", Interval: 2, StartDate: "2024-08-01"},
+			date:     time.Date(2024, time.August, 3, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "every other day - off interval",
+			schedule: config.Schedule{Frequency: "daily", Interval: 2, StartDate: "2024-08-01"},
+			date:     time.Date(2024, time.August, 4, 0, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "before start date",
+			schedule: config.Schedule{Frequency: "daily", StartDate: "2024-08-01"},
+			date:     time.Date(2024, time.July, 31, 0, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "weekly defaults to Monday",
+			schedule: config.Schedule{Frequency: "weekly"},
+			date:     time.Date(2024, time.July, 29, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "weekly defaults to Monday - Tuesday",
+			schedule: config.Schedule{Frequency: "weekly"},
+			date:     time.Date(2024, time.July, 30, 0, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "weekly defaults to start weekday",
+			schedule: config.Schedule{Frequency: "weekly", StartDate: "2024-08-02"},
+			date:     time.Date(2024, time.August, 9, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "fortnightly on Friday - off week",
+			schedule: config.Schedule{Frequency: "weekly", Interval: 2, Days: []int{5}, StartDate: "2024-07-29"},
+			date:     time.Date(2024, time.August, 9, 0, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "fortnightly on Friday - on week",
+			schedule: config.Schedule{Frequency: "weekly", Interval: 2, Days: []int{5}, StartDate: "2024-07-29"},
+			date:     time.Date(2024, time.August, 16, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "weekly on Sunday",
+			schedule: config.Schedule{Frequency: "weekly", Days: []int{7}},
+			date:     time.Date(2024, time.August, 4, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "monthly defaults to the 1st",
+			schedule: config.Schedule{Frequency: "monthly"},
+			date:     time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "monthly on the 31st - short month",
+			schedule: config.Schedule{Frequency: "monthly", Dates: []int{31}},
+			date:     time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "monthly on the 31st - long month",
+			schedule: config.Schedule{Frequency: "monthly", Dates: []int{31}},
+			date:     time.Date(2024, time.May, 30, 0, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "monthly on the Monday of the 2nd week",
+			schedule: config.Schedule{Frequency: "monthly", Weeks: []int{2}},
+			date:     time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "quarterly - on interval",
+			schedule: config.Schedule{Frequency: "monthly", Interval: 3, StartDate: "2024-01-15"},
+			date:     time.Date(2024, time.October, 15, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "quarterly - off interval",
+			schedule: config.Schedule{Frequency: "monthly", Interval: 3, StartDate: "2024-01-15"},
+			date:     time.Date(2024, time.November, 15, 0, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "yearly defaults to start date",
+			schedule: config.Schedule{Frequency: "yearly", StartDate: "2020-12-31"},
+			date:     time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "yearly in selected months",
+			schedule: config.Schedule{Frequency: "yearly", Months: []int{1, 7}, Dates: []int{1}},
+			date:     time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "time of day is ignored",
+			schedule: config.Schedule{Frequency: "daily", StartDate: "2024-08-02"},
+			date:     time.Date(2024, time.August, 2, 23, 59, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name:     "invalid start date",
+			schedule: config.Schedule{Frequency: "daily", StartDate: "02/08/2024"},
+			date:     time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsDue(tt.schedule, tt.date)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule config.Schedule
+		from     time.Time
+		to       time.Time
+		want     []time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "weekdays over a weekend",
+			schedule: config.Schedule{Frequency: "daily", Days: []int{1, 2, 3, 4, 5}},
+			from:     time.Date(2024, time.August, 1, 9, 30, 0, 0, time.UTC),
+			to:       time.Date(2024, time.August, 6, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.August, 5, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.August, 6, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "weekly across a month boundary",
+			schedule: config.Schedule{Frequency: "weekly", Days: []int{5}},
+			from:     time.Date(2024, time.July, 20, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, time.August, 10, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, time.July, 26, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.August, 9, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "nothing due",
+			schedule: config.Schedule{Frequency: "monthly"},
+			from:     time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, time.August, 20, 0, 0, 0, 0, time.UTC),
+			want:     []time.Time{},
+		},
+		{
+			name:     "end before start",
+			schedule: config.Schedule{Frequency: "daily"},
+			from:     time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			to:       time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Occurrences(tt.schedule, tt.from, tt.to)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed search query
+// Queries are made of words and quoted phrases (matched case insensitively), combined with the
+// operators AND (implied between terms), OR and NOT (or a leading "-"), and grouped with parentheses
+// e.g. `"release notes" AND (api OR cli) -draft`
+type Query struct {
+	// root is the expression evaluated against the content of a file
+	root node
+
+	// highlights are the patterns of the terms that are not negated (used to find and highlight hits)
+	highlights []*regexp.Regexp
+}
+
+// node is an expression in a query
+type node interface {
+	// matches reports whether the content satisfies the expression
+	matches(content string) bool
+
+	// positiveTerms returns the patterns of the terms that are not negated
+	// (negated is true if the node is within an odd number of NOTs)
+	positiveTerms(negated bool) []*regexp.Regexp
+}
+
+// termNode matches content containing a word, phrase or regular expression
+type termNode struct {
+	pattern *regexp.Regexp
+}
+
+func (n termNode) matches(content string) bool {
+	return n.pattern.MatchString(content)
+}
+
+func (n termNode) positiveTerms(negated bool) []*regexp.Regexp {
+	if negated {
+		return nil
+	}
+	return []*regexp.Regexp{n.pattern}
+}
+
+// notNode matches content that does not match its operand
+type notNode struct {
+	operand node
+}
+
+func (n notNode) matches(content string) bool {
+	return !n.operand.matches(content)
+}
+
+func (n notNode) positiveTerms(negated bool) []*regexp.Regexp {
+	return n.operand.positiveTerms(!negated)
+}
+
+// andNode matches content that matches all of its operands
+type andNode struct {
+	operands []node
+}
+
+func (n andNode) matches(content string) bool {
+	for _, operand := range n.operands {
+		if !operand.matches(content) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n andNode) positiveTerms(negated bool) []*regexp.Regexp {
+	return collectTerms(n.operands, negated)
+}
+
+// orNode matches content that matches any of its operands
+type orNode struct {
+	operands []node
+}
+
+func (n orNode) matches(content string) bool {
+	for _, operand := range n.operands {
+		if operand.matches(content) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n orNode) positiveTerms(negated bool) []*regexp.Regexp {
+	return collectTerms(n.operands, negated)
+}
+
+// collectTerms returns the positive terms of all of the operands
+func collectTerms(operands []node, negated bool) []*regexp.Regexp {
+	terms := []*regexp.Regexp{}
+	for _, operand := range operands {
+		terms = append(terms, operand.positiveTerms(negated)...)
+	}
+	return terms
+}
+
+// Parse parses a search query (see Query)
+func Parse(query string) (*Query, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("search query is empty")
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q in search query", p.peek().text)
+	}
+	return newQuery(root), nil
+}
+
+// ParseRegex creates a query matching a single regular expression
+// The expression is case sensitive unless it starts with the (?i) flag
+func ParseRegex(expression string) (*Query, error) {
+	if expression == "" {
+		return nil, errors.New("search query is empty")
+	}
+	pattern, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return newQuery(termNode{pattern: pattern}), nil
+}
+
+// newQuery creates a query from the root of its expression
+func newQuery(root node) *Query {
+	return &Query{root: root, highlights: root.positiveTerms(false)}
+}
+
+// tokenKind is the kind of a token in a query
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+// token is a lexical token in a query
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits a query into tokens
+// Operators must be upper case (so "and" is searched for as a word), and a leading "-" negates a term
+func tokenize(query string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		var tok token
+		var err error
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tok, i = token{kind: tokenOpen, text: "("}, i+1
+		case r == ')':
+			tok, i = token{kind: tokenClose, text: ")"}, i+1
+		case isNegation(runes, i):
+			tok, i = token{kind: tokenNot, text: "-"}, i+1
+		case r == '"':
+			tok, i, err = readPhrase(runes, i)
+		default:
+			tok, i = readWord(runes, i)
+		}
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens, nil
+}
+
+// isNegation reports whether the rune at i is a "-" negating the term that follows it
+func isNegation(runes []rune, i int) bool {
+	return runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1])
+}
+
+// readPhrase reads a quoted phrase starting at i, returning the token and the position after it
+func readPhrase(runes []rune, i int) (token, int, error) {
+	end := i + 1
+	for end < len(runes) && runes[end] != '"' {
+		end++
+	}
+	if end == len(runes) {
+		return token{}, end, errors.New("unterminated quote in search query")
+	}
+	return token{kind: tokenTerm, text: string(runes[i+1 : end])}, end + 1, nil
+}
+
+// readWord reads an unquoted word starting at i, returning the token and the position after it
+func readWord(runes []rune, i int) (token, int) {
+	end := i
+	for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+		end++
+	}
+	return wordToken(string(runes[i:end])), end
+}
+
+// wordToken returns the token for an unquoted word (an operator, or a term)
+func wordToken(word string) token {
+	switch word {
+	case "AND":
+		return token{kind: tokenAnd, text: word}
+	case "OR":
+		return token{kind: tokenOr, text: word}
+	case "NOT":
+		return token{kind: tokenNot, text: word}
+	default:
+		return token{kind: tokenTerm, text: word}
+	}
+}
+
+// parser is a recursive descent parser for query tokens
+// or := and ("OR" and)*
+// and := unary ("AND"? unary)*
+// unary := ("NOT" | "-") unary | "(" or ")" | term
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) parseOr() (node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	operands := []node{first}
+	for !p.done() && p.peek().kind == tokenOr {
+		p.pos++
+		operand, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return orNode{operands: operands}, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	operands := []node{first}
+	for !p.done() {
+		switch p.peek().kind {
+		case tokenOr, tokenClose:
+			return andOf(operands), nil
+		case tokenAnd:
+			p.pos++
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	return andOf(operands), nil
+}
+
+// andOf returns the AND of the operands (or the operand itself, if there is only one)
+func andOf(operands []node) node {
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	return andNode{operands: operands}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.done() {
+		return nil, errors.New("search query ends with an operator")
+	}
+	tok := p.peek()
+	p.pos++
+	switch tok.kind {
+	case tokenNot:
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	case tokenOpen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokenClose {
+			return nil, errors.New("missing closing parenthesis in search query")
+		}
+		p.pos++
+		return inner, nil
+	case tokenTerm:
+		return newTermNode(tok.text)
+	default:
+		return nil, fmt.Errorf("unexpected %q in search query", tok.text)
+	}
+}
+
+// newTermNode creates a node matching a word or phrase, case insensitively
+// Words in a phrase may be separated by any amount of whitespace
+func newTermNode(text string) (node, error) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil, errors.New("empty phrase in search query")
+	}
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	pattern, err := regexp.Compile(`(?i)` + strings.Join(words, `\s+`))
+	if err != nil {
+		return nil, err
+	}
+	return termNode{pattern: pattern}, nil
+}
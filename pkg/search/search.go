@@ -0,0 +1,92 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// Hit is a line of a file containing a (non-negated) term of a query
+type Hit struct {
+	// Line is the line number of the hit (starting from 1)
+	Line int `json:"line"`
+
+	// Text is the text of the line
+	Text string `json:"text"`
+
+	// Matches are the byte ranges ([start, end)) of the terms within Text, in order and without overlaps
+	Matches [][]int `json:"matches"`
+
+	// Before are the lines of context before the hit
+	Before []string `json:"before,omitempty"`
+
+	// After are the lines of context after the hit
+	After []string `json:"after,omitempty"`
+}
+
+// Search matches the content of a file against the query
+// If the content matches, the lines containing the query's terms are returned as hits, with up to
+// contextLines lines of context either side (a file can match without hits, e.g. "NOT draft")
+func (q *Query) Search(content string, contextLines int) ([]Hit, bool) {
+	if !q.root.matches(content) {
+		return nil, false
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	hits := []Hit{}
+	for i, line := range lines {
+		matches := q.lineMatches(line)
+		if len(matches) == 0 {
+			continue
+		}
+		hits = append(hits, Hit{
+			Line:    i + 1,
+			Text:    line,
+			Matches: matches,
+			Before:  lines[max(0, i-contextLines):i],
+			After:   lines[i+1 : min(len(lines), i+1+contextLines)],
+		})
+	}
+	return hits, true
+}
+
+// lineMatches returns the merged byte ranges of all of the query's terms within a line
+func (q *Query) lineMatches(line string) [][]int {
+	ranges := [][]int{}
+	for _, pattern := range q.highlights {
+		for _, loc := range pattern.FindAllStringIndex(line, -1) {
+			if loc[1] > loc[0] {
+				ranges = append(ranges, loc)
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i][0] < ranges[j][0]
+	})
+	merged := [][]int{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// Highlight returns the text of the hit with each match wrapped in start and end (e.g. terminal colour codes)
+func (h Hit) Highlight(start string, end string) string {
+	var b strings.Builder
+	pos := 0
+	for _, m := range h.Matches {
+		b.WriteString(h.Text[pos:m[0]])
+		b.WriteString(start)
+		b.WriteString(h.Text[m[0]:m[1]])
+		b.WriteString(end)
+		pos = m[1]
+	}
+	b.WriteString(h.Text[pos:])
+	return b.String()
+}
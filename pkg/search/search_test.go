@@ -0,0 +1,173 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const standup = `# Stand-up: Friday 2nd August 2024
+
+## Yesterday
+- Fixed the release notes for the CLI
+- Reviewed the API docs
+
+## Today
+- Draft the retro agenda`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantMatch bool
+		wantLines []int
+		wantErr   bool
+	}{
+		{
+			name:      "single word - case insensitive",
+			query:     "RELEASE",
+			wantMatch: true,
+			wantLines: []int{4},
+		},
+		{
+			name:      "implied AND",
+			query:     "release retro",
+			wantMatch: true,
+			wantLines: []int{4, 8},
+		},
+		{
+			name:      "implied AND - missing term",
+			query:     "release holiday",
+			wantMatch: false,
+		},
+		{
+			name:      "phrase",
+			query:     `"release notes"`,
+			wantMatch: true,
+			wantLines: []int{4},
+		},
+		{
+			name:      "phrase - words out of order",
+			query:     `"notes release"`,
+			wantMatch: false,
+		},
+		{
+			name:      "OR",
+			query:     "holiday OR api",
+			wantMatch: true,
+			wantLines: []int{5},
+		},
+		{
+			name:      "NOT",
+			query:     "release NOT holiday",
+			wantMatch: true,
+			wantLines: []int{4},
+		},
+		{
+			name:      "leading minus",
+			query:     "release -draft",
+			wantMatch: false,
+		},
+		{
+			name:      "only negated terms - match without hits",
+			query:     "NOT holiday",
+			wantMatch: true,
+			wantLines: []int{},
+		},
+		{
+			name:      "parentheses",
+			query:     `"stand-up" AND (holiday OR retro)`,
+			wantMatch: true,
+			wantLines: []int{1, 8},
+		},
+		{
+			name:      "lower case operators are words",
+			query:     "fixed and reviewed",
+			wantMatch: true,
+			wantLines: []int{1, 4, 5},
+		},
+		{
+			name:    "empty query",
+			query:   "  ",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated quote",
+			query:   `"release notes`,
+			wantErr: true,
+		},
+		{
+			name:    "missing closing parenthesis",
+			query:   "(release OR retro",
+			wantErr: true,
+		},
+		{
+			name:    "unexpected closing parenthesis",
+			query:   "release)",
+			wantErr: true,
+		},
+		{
+			name:    "trailing operator",
+			query:   "release OR",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			hits, ok := q.Search(standup, 0)
+			assert.Equal(t, tt.wantMatch, ok)
+			if !tt.wantMatch {
+				assert.Empty(t, hits)
+				return
+			}
+			lines := []int{}
+			for _, hit := range hits {
+				lines = append(lines, hit.Line)
+			}
+			assert.Equal(t, tt.wantLines, lines)
+		})
+	}
+}
+
+func TestParseRegex(t *testing.T) {
+	q, err := ParseRegex(`(?i)re(view|lease)`)
+	assert.NoError(t, err)
+	hits, ok := q.Search(standup, 0)
+	assert.True(t, ok)
+	assert.Len(t, hits, 2)
+	assert.Equal(t, [][]int{{12, 19}}, hits[0].Matches)
+
+	q, err = ParseRegex(`Release`)
+	assert.NoError(t, err)
+	_, ok = q.Search(standup, 0)
+	assert.False(t, ok, "regular expressions are case sensitive")
+
+	_, err = ParseRegex(`re(`)
+	assert.Error(t, err)
+}
+
+func TestSearchContext(t *testing.T) {
+	q, err := Parse("api")
+	assert.NoError(t, err)
+	hits, ok := q.Search(standup, 2)
+	assert.True(t, ok)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, []string{"## Yesterday", "- Fixed the release notes for the CLI"}, hits[0].Before)
+	assert.Equal(t, []string{"", "## Today"}, hits[0].After)
+}
+
+func TestHighlight(t *testing.T) {
+	q, err := Parse(`"the release" release notes`)
+	assert.NoError(t, err)
+	hits, ok := q.Search(standup, 0)
+	assert.True(t, ok)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, "- Fixed [the release] [notes] for the CLI", hits[0].Highlight("[", "]"))
+}
@@ -0,0 +1,70 @@
+package tags
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/matthewchivers/journal/pkg/frontmatter"
+)
+
+// FrontMatterKey is the front matter field containing an entry's tags
+const FrontMatterKey = "tags"
+
+// hashtagRegex matches #hashtags (but not markdown headings, which are followed by a space or another #)
+var hashtagRegex = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_][\p{L}\p{N}_/-]*)`)
+
+// Normalise returns a tag in its canonical form: lower case, without a leading "#", and with
+// whitespace replaced by "-" (so "#Client X" and "client-x" are the same tag)
+func Normalise(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	return strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+}
+
+// Merge returns the distinct, normalised tags of all of the lists, sorted (nil if there are none)
+func Merge(lists ...[]string) []string {
+	seen := map[string]bool{}
+	merged := []string{}
+	for _, list := range lists {
+		for _, tag := range list {
+			tag = Normalise(tag)
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				merged = append(merged, tag)
+			}
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// Extract returns the tags of a document: the tags in its front matter, and the #hashtags in its body
+func Extract(content string) []string {
+	fields, body, err := frontmatter.Parse(content)
+	if err != nil {
+		// Treat content with broken front matter as having none, rather than losing its hashtags
+		fields, body = nil, content
+	}
+	hashtags := []string{}
+	for _, match := range hashtagRegex.FindAllStringSubmatch(body, -1) {
+		hashtags = append(hashtags, match[1])
+	}
+	return Merge(fields.Strings(FrontMatterKey), hashtags)
+}
+
+// ContainsAll reports whether tags contains every one of the wanted tags (compared in normalised form)
+func ContainsAll(tags []string, wanted []string) bool {
+	have := map[string]bool{}
+	for _, tag := range tags {
+		have[Normalise(tag)] = true
+	}
+	for _, tag := range wanted {
+		if !have[Normalise(tag)] {
+			return false
+		}
+	}
+	return true
+}
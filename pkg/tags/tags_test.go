@@ -0,0 +1,69 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalise(t *testing.T) {
+	assert.Equal(t, "client-x", Normalise(" #Client  X "))
+	assert.Equal(t, "project/alpha", Normalise("Project/Alpha"))
+	assert.Equal(t, "", Normalise(" # "))
+}
+
+func TestMerge(t *testing.T) {
+	assert.Equal(t, []string{"client-x", "planning", "work"}, Merge([]string{"Work", "#planning"}, []string{"client x", "work", ""}))
+	assert.Nil(t, Merge(nil, []string{" "}))
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "hashtags",
+			content: "#work notes about #Project-X and #team/platform",
+			want:    []string{"project-x", "team/platform", "work"},
+		},
+		{
+			name:    "headings are not tags",
+			content: "# Heading\n## Subheading\n### Third",
+			want:    nil,
+		},
+		{
+			name:    "anchors and issue references in words are not tags",
+			content: "see page#section and C# code",
+			want:    nil,
+		},
+		{
+			name:    "front matter list and hashtags",
+			content: "---\ntags: [Client X, planning]\n---\n# Planning\nAgreed the #roadmap with #planning\n",
+			want:    []string{"client-x", "planning", "roadmap"},
+		},
+		{
+			name:    "front matter comma separated string",
+			content: "---\ntags: \"work, client-x\"\n---\n",
+			want:    []string{"client-x", "work"},
+		},
+		{
+			name:    "broken front matter",
+			content: "---\ntags: [work\n#roadmap\n",
+			want:    []string{"roadmap"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Extract(tt.content))
+		})
+	}
+}
+
+func TestContainsAll(t *testing.T) {
+	assert.True(t, ContainsAll([]string{"client-x", "work"}, []string{"#Work", "Client X"}))
+	assert.True(t, ContainsAll([]string{"work"}, nil))
+	assert.False(t, ContainsAll([]string{"work"}, []string{"work", "planning"}))
+	assert.False(t, ContainsAll(nil, []string{"work"}))
+}
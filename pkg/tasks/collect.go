@@ -0,0 +1,134 @@
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+)
+
+// idLength is the number of hex characters in an item ID
+const idLength = 7
+
+// Item is an open task in a journal entry
+type Item struct {
+	// Record is the entry the task is in
+	catalog.Record
+
+	// ID identifies the task (derived from its file, text and occurrence within the file)
+	ID string `json:"id"`
+
+	// Line is the (1-based) line number of the task in its file
+	Line int `json:"line"`
+
+	// Text is the text of the task
+	Text string `json:"text"`
+
+	// Since is the date of the first entry the task was open in
+	Since time.Time `json:"since"`
+}
+
+// itemKey identifies the same task carried over from entry to entry
+type itemKey struct {
+	entryID string
+	topic   string
+	text    string
+}
+
+// Collect returns the open tasks in the files of the records (which must be sorted by date), sorted by entry ID,
+// topic, and then by age (oldest first)
+// A task carried over from entry to entry (of the same type and topic) is returned once: from the most recent
+// entry it appears in, and only if it is still open there
+// Encrypted files are skipped
+func Collect(records []catalog.Record) ([]Item, error) {
+	open := map[itemKey]*Item{}
+	for _, record := range records {
+		if record.Encrypted {
+			// The tasks of encrypted entries are kept private
+			continue
+		}
+		content, ok, err := catalog.ReadRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		date := record.Date
+		if date.IsZero() {
+			if info, err := os.Stat(record.Path); err == nil {
+				date = info.ModTime()
+			}
+		}
+		occurrences := map[string]int{}
+		for _, task := range Parse(content) {
+			key := itemKey{entryID: record.EntryID, topic: record.Topic, text: task.Text}
+			occurrences[task.Text]++
+			if task.Done {
+				delete(open, key)
+				continue
+			}
+			item, ok := open[key]
+			if !ok {
+				item = &Item{Text: task.Text, Since: date}
+				open[key] = item
+			}
+			item.Record = record
+			item.Line = task.Line
+			item.ID = itemID(record.Path, task.Text, occurrences[task.Text])
+		}
+	}
+	return sortItems(open), nil
+}
+
+// sortItems returns the items sorted by entry ID, topic, and then by age (oldest first)
+func sortItems(open map[itemKey]*Item) []Item {
+	items := []Item{}
+	for _, item := range open {
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.EntryID != b.EntryID {
+			return a.EntryID < b.EntryID
+		}
+		if a.Topic != b.Topic {
+			return a.Topic < b.Topic
+		}
+		if !a.Since.Equal(b.Since) {
+			return a.Since.Before(b.Since)
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+	return items
+}
+
+// itemID returns the ID of the nth occurrence of a task in a file
+func itemID(filePath, text string, occurrence int) string {
+	sum := sha256.Sum256([]byte(filePath + "\x00" + text + "\x00" + strconv.Itoa(occurrence)))
+	return hex.EncodeToString(sum[:])[:idLength]
+}
+
+// Complete checks the task on the given (1-based) line of a Markdown document, returning the updated document
+// The line must still contain the open task with the given text (i.e. the file has not changed since it was read)
+func Complete(content string, line int, text string) (string, error) {
+	lines := strings.Split(content, "\n")
+	if line < 1 || line > len(lines) {
+		return "", fmt.Errorf("line %d is out of range", line)
+	}
+	task := Parse(lines[line-1])
+	if len(task) != 1 || task[0].Done || task[0].Text != text {
+		return "", fmt.Errorf("open task %q not found on line %d", text, line)
+	}
+	lines[line-1] = strings.Replace(lines[line-1], "[ ]", "[x]", 1)
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,101 @@
+package tasks
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/catalog/catalogtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollect(t *testing.T) {
+	tempDir := t.TempDir()
+	day := func(d int) time.Time { return time.Date(2024, time.August, d, 0, 0, 0, 0, time.UTC) }
+	records := catalogtest.WriteFiles(t, tempDir,
+		catalogtest.File{
+			Name:    "standup-01.md",
+			Record:  catalog.Record{EntryID: "standup", Date: day(1)},
+			Content: "- [ ] write report\n- [ ] fix build\n- [ ] book room\n",
+		},
+		catalogtest.File{
+			Name:    "standup-02.md",
+			Record:  catalog.Record{EntryID: "standup", Date: day(2)},
+			Content: "- [ ] write report\n- [x] fix build\n",
+		},
+		catalogtest.File{
+			Name:    "planning-02.md",
+			Record:  catalog.Record{EntryID: "meeting", Topic: "planning", Date: day(2)},
+			Content: "# Actions\n- [ ] send minutes\n",
+		},
+		catalogtest.File{
+			Name:    "standup-03.md",
+			Record:  catalog.Record{EntryID: "standup", Date: day(3)},
+			Content: "- [ ] update roadmap\n- [ ] write report\n",
+		},
+	)
+	records = append(records, catalog.Record{EntryID: "standup", Path: filepath.Join(tempDir, "removed.md")})
+
+	items, err := Collect(records)
+	assert.NoError(t, err)
+	got := []string{}
+	for _, item := range items {
+		assert.Len(t, item.ID, idLength)
+		got = append(got, item.EntryID+"|"+item.Text+"|"+item.Since.Format(time.DateOnly)+"|"+filepath.Base(item.Path))
+	}
+	assert.Equal(t, []string{
+		"meeting|send minutes|2024-08-02|planning-02.md",
+		"standup|book room|2024-08-01|standup-01.md",
+		"standup|write report|2024-08-01|standup-03.md",
+		"standup|update roadmap|2024-08-03|standup-03.md",
+	}, got)
+	assert.Equal(t, 2, items[2].Line)
+}
+
+func TestComplete(t *testing.T) {
+	content := "# Actions\n- [ ] send minutes\n- [x] book room\n"
+	tests := []struct {
+		name    string
+		line    int
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "open task",
+			line: 2,
+			text: "send minutes",
+			want: "# Actions\n- [x] send minutes\n- [x] book room\n",
+		},
+		{
+			name:    "task already done",
+			line:    3,
+			text:    "book room",
+			wantErr: true,
+		},
+		{
+			name:    "file changed",
+			line:    2,
+			text:    "book room",
+			wantErr: true,
+		},
+		{
+			name:    "line out of range",
+			line:    10,
+			text:    "send minutes",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Complete(content, tt.line, tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
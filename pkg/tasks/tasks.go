@@ -0,0 +1,83 @@
+package tasks
+
+import (
+	"regexp"
+	"strings"
+)
+
+// taskRegex matches a Markdown task list item (e.g. "- [ ] write report", "  * [x] done")
+var taskRegex = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*?)\s*$`)
+
+// Task is a Markdown task list item
+type Task struct {
+	// Line is the (1-based) line number of the task
+	Line int
+
+	// Indent is the indentation of the task (nested tasks are indented below their parent)
+	Indent string
+
+	// Done is true if the task is checked ("- [x]")
+	Done bool
+
+	// Text is the text of the task
+	Text string
+}
+
+// Parse returns the task list items in a Markdown document, in order
+// Items within fenced code blocks are ignored
+func Parse(content string) []Task {
+	tasks := []Task{}
+	inFence := false
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		match := taskRegex.FindStringSubmatch(line)
+		if match == nil || match[4] == "" {
+			continue
+		}
+		tasks = append(tasks, Task{
+			Line:   i + 1,
+			Indent: match[1],
+			Done:   match[3] != " ",
+			Text:   match[4],
+		})
+	}
+	return tasks
+}
+
+// Open returns the unchecked task list items in a Markdown document, in order
+func Open(content string) []Task {
+	open := []Task{}
+	for _, task := range Parse(content) {
+		if !task.Done {
+			open = append(open, task)
+		}
+	}
+	return open
+}
+
+// String returns the task as a Markdown task list item (without its indentation)
+func (t Task) String() string {
+	box := " "
+	if t.Done {
+		box = "x"
+	}
+	return "- [" + box + "] " + t.Text
+}
+
+// List returns the tasks as a Markdown task list, one item per line (empty if there are no tasks)
+// Indentation is discarded, so nested tasks whose parent was finished are not left orphaned
+func List(tasks []Task) string {
+	var sb strings.Builder
+	for _, task := range tasks {
+		sb.WriteString(task.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
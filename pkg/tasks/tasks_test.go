@@ -0,0 +1,41 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	content := "---\ndate: 2024-08-01\n---\n" +
+		"# Stand-up\n" +
+		"- [ ] write report\n" +
+		"- [x] review PR\n" +
+		"  * [ ] nested item  \n" +
+		"1. [X] numbered\n" +
+		"- [ ]\n" +
+		"- [] not a task\n" +
+		"```\n- [ ] in a code block\n```\n" +
+		"+ [ ] last one"
+	want := []Task{
+		{Line: 5, Text: "write report"},
+		{Line: 6, Done: true, Text: "review PR"},
+		{Line: 7, Indent: "  ", Text: "nested item"},
+		{Line: 8, Done: true, Text: "numbered"},
+		{Line: 14, Text: "last one"},
+	}
+	assert.Equal(t, want, Parse(content))
+	assert.Empty(t, Parse("no tasks here"))
+}
+
+func TestOpen(t *testing.T) {
+	content := "- [ ] one\n- [x] two\n  - [ ] three\n"
+	open := Open(content)
+	assert.Equal(t, []Task{
+		{Line: 1, Text: "one"},
+		{Line: 3, Indent: "  ", Text: "three"},
+	}, open)
+	assert.Equal(t, "- [ ] one\n- [ ] three\n", List(open))
+	assert.Equal(t, "", List(nil))
+	assert.Equal(t, "- [x] done", Task{Done: true, Text: "done"}.String())
+}
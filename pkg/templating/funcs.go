@@ -0,0 +1,103 @@
+package templating
+
+import (
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FuncMap returns the functions available in patterns and document templates
+// Functions taking a value to transform take it as their last argument, so that they can be used in pipelines
+// (e.g. {{.Topic | slugify}}, {{.Time | addDays 1 | format "2006-01-02"}})
+func FuncMap() map[string]interface{} {
+	return map[string]interface{}{
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"title":    title,
+		"slugify":  slugify,
+		"truncate": truncate,
+		"replace":  replace,
+		"default":  defaultValue,
+		"addDays":  addDays,
+		"addWeeks": addWeeks,
+		"format":   format,
+		"env":      os.Getenv,
+		"join":     join,
+	}
+}
+
+// title returns s with the first letter of each word in upper case (e.g. "project review" => "Project Review")
+func title(s string) string {
+	var titled strings.Builder
+	startOfWord := true
+	for _, r := range s {
+		if startOfWord {
+			r = unicode.ToUpper(r)
+		}
+		titled.WriteRune(r)
+		startOfWord = unicode.IsSpace(r) || r == '-' || r == '_'
+	}
+	return titled.String()
+}
+
+// slugify returns s in a form safe to use in paths: lower case letters and numbers, with every run of other
+// characters replaced by a single "-" (e.g. "Project A / Q3" => "project-a-q3")
+func slugify(s string) string {
+	var slug strings.Builder
+	pendingDash := false
+	for _, r := range strings.ToLower(s) {
+		if !unicode.IsLetter(r) && !unicode.IsNumber(r) {
+			pendingDash = slug.Len() > 0
+			continue
+		}
+		if pendingDash {
+			slug.WriteRune('-')
+			pendingDash = false
+		}
+		slug.WriteRune(r)
+	}
+	return slug.String()
+}
+
+// truncate returns the first length characters of s (or s, if it is no longer than length)
+func truncate(length int, s string) string {
+	if length < 0 || utf8.RuneCountInString(s) <= length {
+		return s
+	}
+	return string([]rune(s)[:length])
+}
+
+// replace returns s with every occurrence of old replaced by new
+func replace(old string, new string, s string) string {
+	return strings.ReplaceAll(s, old, new)
+}
+
+// defaultValue returns value, or fallback if value is empty (e.g. {{.Topic | default "general"}})
+func defaultValue(fallback string, value string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// addDays returns the time the given number of days after t (before t, if days is negative)
+func addDays(days int, t time.Time) time.Time {
+	return t.AddDate(0, 0, days)
+}
+
+// addWeeks returns the time the given number of weeks after t (before t, if weeks is negative)
+func addWeeks(weeks int, t time.Time) time.Time {
+	return t.AddDate(0, 0, weeks*7)
+}
+
+// format formats t with a Go time layout (e.g. "2006-01-02")
+func format(layout string, t time.Time) string {
+	return t.Format(layout)
+}
+
+// join returns the elements of a list (e.g. .Tags) separated by sep
+func join(sep string, elems []string) string {
+	return strings.Join(elems, sep)
+}
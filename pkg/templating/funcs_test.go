@@ -0,0 +1,50 @@
+package templating
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuncMap(t *testing.T) {
+	t.Setenv("JOURNAL_TEST_TEAM", "platform")
+	testTime := time.Date(2024, 6, 28, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		pattern  string
+		expected string
+	}{
+		{name: "upper", pattern: "{{.EntryID | upper}}", expected: "STANDUP"},
+		{name: "lower", pattern: "{{.Topic | lower}}", expected: "project a / q3"},
+		{name: "title", pattern: "{{\"weekly project-review\" | title}}", expected: "Weekly Project-Review"},
+		{name: "slugify", pattern: "{{.Topic | slugify}}", expected: "project-a-q3"},
+		{name: "slugify punctuation only", pattern: "{{\" / \" | slugify}}", expected: ""},
+		{name: "truncate", pattern: "{{.Topic | truncate 7}}", expected: "Project"},
+		{name: "truncate short value", pattern: "{{.EntryID | truncate 20}}", expected: "standup"},
+		{name: "replace", pattern: "{{.Topic | replace \" / \" \"_\"}}", expected: "Project A_Q3"},
+		{name: "default", pattern: "{{\"\" | default \"general\"}}", expected: "general"},
+		{name: "default not needed", pattern: "{{.EntryID | default \"general\"}}", expected: "standup"},
+		{name: "add days", pattern: "{{.Time | addDays 3 | format \"2006-01-02\"}}", expected: "2024-07-01"},
+		{name: "add weeks", pattern: "{{.Time | addWeeks -1 | format \"Mon 2 Jan\"}}", expected: "Fri 21 Jun"},
+		{name: "env", pattern: "{{env \"JOURNAL_TEST_TEAM\"}}", expected: "platform"},
+		{name: "join", pattern: "{{.Tags | join \"_\"}}", expected: "client-x_planning"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateData, _ := PrepareTemplateData(testTime)
+			templateData.EntryID = "standup"
+			templateData.Topic = "Project A / Q3"
+			templateData.Tags = []string{"client-x", "planning"}
+			parsedPath, err := templateData.ParsePattern(tt.pattern)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, parsedPath)
+
+			document, err := templateData.ParseDocument("test", tt.pattern)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, document)
+		})
+	}
+}
@@ -0,0 +1,610 @@
+package templating
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/caltools"
+)
+
+// ErrNoMatch is returned when a path could not have been produced by a pattern
+var ErrNoMatch = errors.New("path does not match pattern")
+
+// leafExpressions maps the final element of a field (e.g. "Ord" in "Day.Ord") to the regular expression matching its values
+var leafExpressions = map[string]string{
+	"Num":    `\d{1,3}`,
+	"Pad":    `\d{2,3}`,
+	"Ord":    `\d{1,3}(?:st|nd|rd|th)`,
+	"Name":   `[A-Za-z]+`,
+	"Short":  `[A-Za-z]{3}`,
+	"DaysIn": `\d{2,3}`,
+}
+
+// fieldExpressions maps fields whose values do not follow the pattern of their final element
+// to the regular expression matching their values
+var fieldExpressions = map[string]string{
+	"Year.Num":      `\d{4}`,
+	"Year.Short":    `\d{2}`,
+	"EntryID":       `[^/]+?`,
+	"FileExtension": `[^/.]+`,
+	"Topic":         `.+?`,
+}
+
+// Matcher matches paths against a pattern, recovering the values used to produce them
+// It is the inverse of TemplateModel.ParsePattern
+type Matcher struct {
+	// pattern is the pattern the matcher was created from
+	pattern string
+
+	// literals are the known values of fields, substituted into the pattern before matching
+	literals map[string]string
+
+	// regex is the regular expression equivalent of the pattern
+	regex *regexp.Regexp
+
+	// fields are the (canonical) names of the fields captured by each group in the regular expression
+	// (empty for groups captured by pipelines that do not recover a field, e.g. {{.Time | format "Jan 2"}})
+	fields []string
+
+	// checks are the actions rendered to verify the values captured by each date dependent group
+	// (nil for groups that do not depend on the date)
+	checks []*template.Template
+
+	// layouts are the layouts of the groups that format the time, from which the date can be recovered
+	// (nil for other groups)
+	layouts []*timeLayout
+
+	// hasDateFields is true if the pattern contains any date fields (or pipelines that depend on the date)
+	hasDateFields bool
+
+	// capturesDateFields is true if the pattern captures any plain date fields (e.g. {{.Day.Pad}})
+	capturesDateFields bool
+}
+
+// timeLayout is the layout of a pipeline that formats the time (e.g. {{.Time | addDays 1 | format "2006-01-02"}})
+type timeLayout struct {
+	// layout is the Go time layout the time is formatted with
+	layout string
+
+	// days is the number of days the time is moved by before it is formatted
+	days int
+}
+
+// PatternMatch contains the values recovered from a path
+type PatternMatch struct {
+	// Date is the date recovered from the path
+	// If the pattern does not identify a single day (e.g. it only contains the week commencing date),
+	// the earliest date that produces the path is used
+	// Without a full set of date fields, the date is recovered from the time formatted by a pipeline
+	// (e.g. {{.Time | format "2006-01-02"}}), if there is one
+	// Date is zero if the pattern contains no date fields, or too few to recover a date from
+	Date time.Time
+
+	// EntryID is the entry ID recovered from the path (if the pattern contains one)
+	EntryID string
+
+	// Topic is the topic recovered from the path (if the pattern contains one)
+	Topic string
+
+	// FileExtension is the file extension recovered from the path (if the pattern contains one)
+	FileExtension string
+}
+
+// ReverseParsePattern recovers the date, entry ID, topic and file extension that produced a path from a pattern
+// Returns ErrNoMatch if the path could not have been produced by the pattern
+func ReverseParsePattern(pattern string, path string, loc *time.Location) (*PatternMatch, error) {
+	matcher, err := NewMatcher(pattern, nil)
+	if err != nil {
+		return nil, err
+	}
+	match, ok := matcher.Match(path, loc)
+	if !ok {
+		return nil, ErrNoMatch
+	}
+	return match, nil
+}
+
+// NewMatcher creates a Matcher for the given pattern
+// Fields with known values (e.g. the EntryID of an entry type) can be provided as literals,
+// which are substituted into the pattern before matching
+func NewMatcher(pattern string, literals map[string]string) (*Matcher, error) {
+	t, err := newPatternTemplate(pattern)
+	if err != nil {
+		return nil, err
+	}
+	// Patterns that cannot be rendered cannot be matched either
+	sample, err := PrepareTemplateData(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Execute(io.Discard, sample); err != nil {
+		return nil, err
+	}
+
+	tree, err := texttemplate.New("path").Funcs(FuncMap()).Parse(pattern)
+	if err != nil {
+		return nil, err
+	}
+	matcher := &Matcher{pattern: pattern, literals: literals}
+	var expression strings.Builder
+	expression.WriteString("^")
+	for _, node := range tree.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			expression.WriteString(regexp.QuoteMeta(string(n.Text)))
+		case *parse.ActionNode:
+			actionExpression, err := matcher.actionExpression(n, sample)
+			if err != nil {
+				return nil, err
+			}
+			expression.WriteString(actionExpression)
+		default:
+			return nil, fmt.Errorf("unsupported pattern element: %s", node)
+		}
+	}
+	expression.WriteString("$")
+	if matcher.hasDateFields && !matcher.capturesDateFields && !matcher.hasTimeLayouts() {
+		return nil, fmt.Errorf("the date cannot be recovered from %q: use date fields, or format .Time with a layout that includes the year, month and day", pattern)
+	}
+	regex, err := regexp.Compile(expression.String())
+	if err != nil {
+		return nil, err
+	}
+	matcher.regex = regex
+	return matcher, nil
+}
+
+// actionExpression returns the regular expression for a pattern action, registering a capture group for it
+func (m *Matcher) actionExpression(action *parse.ActionNode, sample TemplateModel) (string, error) {
+	if field, ok := actionField(action); ok && hasFieldExpression(field) {
+		return m.fieldExpression(field, action)
+	}
+	return m.pipelineExpression(action, sample)
+}
+
+// actionField returns the name of the field (e.g. "Year.Num") referenced by a pattern action that is a single field
+func actionField(action *parse.ActionNode) (string, bool) {
+	if len(action.Pipe.Decl) != 0 || len(action.Pipe.Cmds) != 1 || len(action.Pipe.Cmds[0].Args) != 1 {
+		return "", false
+	}
+	field, ok := action.Pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok {
+		return "", false
+	}
+	return strings.Join(field.Ident, "."), true
+}
+
+// hasFieldExpression reports whether the values of a field can be matched by a regular expression
+func hasFieldExpression(field string) bool {
+	if _, ok := fieldExpressions[strings.TrimPrefix(field, "WkCom.")]; ok {
+		return true
+	}
+	idents := strings.Split(field, ".")
+	_, ok := leafExpressions[idents[len(idents)-1]]
+	return ok
+}
+
+// pipelineExpression returns the regular expression for an action that is not a single field
+// (e.g. {{.Topic | slugify}}), registering a capture group for it
+// Pipelines whose fields all have known values are matched literally, and a pipeline of a single non-date
+// field recovers that field as it appears in the path (e.g. the slug of the topic)
+func (m *Matcher) pipelineExpression(action *parse.ActionNode, sample TemplateModel) (string, error) {
+	check, err := newPatternTemplate(action.String())
+	if err != nil {
+		return "", err
+	}
+	fields, dynamic := pipelineFields(action.Pipe)
+	if !dynamic && m.allLiterals(fields) {
+		var rendered strings.Builder
+		if err := check.Execute(&rendered, m.literalModel(sample)); err != nil {
+			return "", err
+		}
+		return regexp.QuoteMeta(rendered.String()), nil
+	}
+	field := ""
+	layout, _ := newTimeLayout(action.Pipe)
+	if dynamic || hasDateField(fields) {
+		m.hasDateFields = true
+	} else {
+		check = nil
+		if len(fields) == 1 && isRecoveredField(fields[0]) {
+			field = fields[0]
+		}
+	}
+	m.addGroup(field, check, layout)
+	return "(.*?)", nil
+}
+
+// addGroup registers a capture group of the regular expression
+func (m *Matcher) addGroup(field string, check *template.Template, layout *timeLayout) {
+	m.fields = append(m.fields, field)
+	m.checks = append(m.checks, check)
+	m.layouts = append(m.layouts, layout)
+}
+
+// hasTimeLayouts reports whether any group formats the time with a layout the date can be recovered from
+func (m *Matcher) hasTimeLayouts() bool {
+	for _, layout := range m.layouts {
+		if layout != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// newTimeLayout returns the layout of a pipeline that formats the time, optionally moved by a number of days or
+// weeks (e.g. {{.Time | addDays 1 | format "2006-01-02"}})
+// Returns false if the pipeline is not of this form, or its layout does not identify a single day
+func newTimeLayout(pipe *parse.PipeNode) (*timeLayout, bool) {
+	cmds := pipe.Cmds
+	if len(pipe.Decl) != 0 || len(cmds) < 2 || len(cmds[0].Args) != 1 {
+		return nil, false
+	}
+	if field, ok := cmds[0].Args[0].(*parse.FieldNode); !ok || strings.Join(field.Ident, ".") != "Time" {
+		return nil, false
+	}
+	layout := &timeLayout{}
+	for i, cmd := range cmds[1:] {
+		if !layout.apply(cmd, i == len(cmds)-2) {
+			return nil, false
+		}
+	}
+	if !identifiesDay(layout.layout) {
+		return nil, false
+	}
+	return layout, true
+}
+
+// apply adds a command of a time pipeline to the layout: addDays or addWeeks with a constant, or (last) format
+// with a constant layout
+func (l *timeLayout) apply(cmd *parse.CommandNode, last bool) bool {
+	if len(cmd.Args) != 2 {
+		return false
+	}
+	function, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return false
+	}
+	if last {
+		layout, ok := cmd.Args[1].(*parse.StringNode)
+		if !ok || function.Ident != "format" {
+			return false
+		}
+		l.layout = layout.Text
+		return true
+	}
+	number, ok := cmd.Args[1].(*parse.NumberNode)
+	if !ok || !number.IsInt {
+		return false
+	}
+	switch function.Ident {
+	case "addDays":
+		l.days += int(number.Int64)
+	case "addWeeks":
+		l.days += int(number.Int64) * 7
+	default:
+		return false
+	}
+	return true
+}
+
+// identifiesDay reports whether a time layout includes the year, month and day (or day of the year), so that a
+// date can be recovered from the times it formats
+func identifiesDay(layout string) bool {
+	sample := time.Date(2023, time.November, 29, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(layout, sample.Format(layout))
+	return err == nil && parsed.Year() == sample.Year() && parsed.YearDay() == sample.YearDay()
+}
+
+// date recovers the date from a value formatted with the layout
+func (l *timeLayout) date(value string, loc *time.Location) (time.Time, bool) {
+	parsed, err := time.ParseInLocation(l.layout, html.UnescapeString(value), loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed.AddDate(0, 0, -l.days), true
+}
+
+// pipelineFields returns the names of the fields referenced by a pipeline
+// dynamic is true if the pipeline refers to the data other than through fields (e.g. with variables)
+func pipelineFields(pipe *parse.PipeNode) (fields []string, dynamic bool) {
+	if len(pipe.Decl) != 0 {
+		dynamic = true
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				fields = append(fields, strings.Join(a.Ident, "."))
+			case *parse.PipeNode:
+				nestedFields, nestedDynamic := pipelineFields(a)
+				fields = append(fields, nestedFields...)
+				dynamic = dynamic || nestedDynamic
+			case *parse.ChainNode, *parse.VariableNode, *parse.DotNode:
+				dynamic = true
+			}
+		}
+	}
+	return fields, dynamic
+}
+
+// isRecoveredField reports whether a field is one recovered by name from paths (rather than a date field)
+func isRecoveredField(field string) bool {
+	switch field {
+	case "EntryID", "Topic", "FileExtension":
+		return true
+	}
+	return false
+}
+
+// hasDateField reports whether any of the fields depend on the date
+func hasDateField(fields []string) bool {
+	for _, field := range fields {
+		switch strings.Split(field, ".")[0] {
+		case "EntryID", "Topic", "FileExtension", "Tags", "CarryOver":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// allLiterals reports whether every one of the fields has a known value
+func (m *Matcher) allLiterals(fields []string) bool {
+	for _, field := range fields {
+		if _, ok := m.literals[field]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// literalModel returns the sample model with the known values of fields substituted into it
+func (m *Matcher) literalModel(sample TemplateModel) TemplateModel {
+	sample.EntryID = m.literals["EntryID"]
+	sample.Topic = m.literals["Topic"]
+	sample.FileExtension = m.literals["FileExtension"]
+	return sample
+}
+
+// fieldExpression returns the regular expression for a field, registering a capture group for it
+func (m *Matcher) fieldExpression(field string, action *parse.ActionNode) (string, error) {
+	if literal, ok := m.literals[field]; ok {
+		return regexp.QuoteMeta(literal), nil
+	}
+	expression, ok := fieldExpressions[strings.TrimPrefix(field, "WkCom.")]
+	if !ok {
+		idents := strings.Split(field, ".")
+		expression = leafExpressions[idents[len(idents)-1]]
+	}
+	var check *template.Template
+	if !isRecoveredField(field) {
+		m.hasDateFields = true
+		m.capturesDateFields = true
+		var err error
+		if check, err = newPatternTemplate(action.String()); err != nil {
+			return "", err
+		}
+	}
+	m.addGroup(canonicalField(field), check, nil)
+	return "(" + expression + ")", nil
+}
+
+// canonicalField returns the canonical name of a field, so that fields holding the same value
+// share a name (e.g. "Year.Month.Day.Num" and "Month.Day.Num" are both "Day.Num")
+func canonicalField(field string) string {
+	prefix := ""
+	if strings.HasPrefix(field, "WkCom.") {
+		prefix, field = "WkCom.", strings.TrimPrefix(field, "WkCom.")
+	}
+	field = strings.TrimPrefix(field, "Year.Month.")
+	switch {
+	case strings.HasPrefix(field, "Month.Day."):
+		field = "Day." + strings.TrimPrefix(field, "Month.Day.")
+	case strings.HasPrefix(field, "Year.Week.Day."), strings.HasPrefix(field, "Month.Week.Day."):
+		field = "Weekday." + field[strings.LastIndex(field, ".")+1:]
+	case field == "Day.Name" || field == "Day.Short":
+		field = "Weekday." + strings.TrimPrefix(field, "Day.")
+	}
+	return prefix + field
+}
+
+// Match matches a (slash separated) path against the pattern
+// Returns false if the path could not have been produced by the pattern
+func (m *Matcher) Match(path string, loc *time.Location) (*PatternMatch, bool) {
+	groups := m.regex.FindStringSubmatch(path)
+	if groups == nil {
+		return nil, false
+	}
+	values := map[string]string{}
+	for i, field := range m.fields {
+		if _, ok := values[field]; !ok && field != "" {
+			values[field] = groups[i+1]
+		}
+	}
+	match := &PatternMatch{
+		EntryID:       m.valueOf("EntryID", values),
+		Topic:         m.valueOf("Topic", values),
+		FileExtension: m.valueOf("FileExtension", values),
+	}
+
+	if !m.hasDateFields {
+		return match, true
+	}
+	candidates := candidateDates(values, loc)
+	if len(candidates) == 0 {
+		candidates = m.formattedDates(groups, loc)
+	}
+	if len(candidates) == 0 && m.capturesDateFields {
+		// Too few date fields to recover a date from, so the path cannot be verified any further
+		return match, true
+	}
+	for _, candidate := range candidates {
+		if m.produces(groups, match, candidate) {
+			match.Date = candidate
+			return match, true
+		}
+	}
+	return nil, false
+}
+
+// formattedDates returns the dates recovered from the groups that format the time
+func (m *Matcher) formattedDates(groups []string, loc *time.Location) []time.Time {
+	dates := []time.Time{}
+	for i, layout := range m.layouts {
+		if layout == nil {
+			continue
+		}
+		if date, ok := layout.date(groups[i+1], loc); ok {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// valueOf returns the value of a field, either captured from the path or provided as a literal
+// Captured values are unescaped, as patterns are rendered with HTML escaping (e.g. "R&amp;D" => "R&D")
+func (m *Matcher) valueOf(field string, values map[string]string) string {
+	if literal, ok := m.literals[field]; ok {
+		return literal
+	}
+	return html.UnescapeString(values[field])
+}
+
+// produces reports whether rendering the pattern with the matched values and the given date produces the
+// captured values of every date dependent group
+// Only the date dependent groups are rendered, as the other values are the path as written (with any HTML
+// escaping applied when it was created), which would not render back to the same text
+func (m *Matcher) produces(groups []string, match *PatternMatch, date time.Time) bool {
+	model, err := PrepareTemplateData(date)
+	if err != nil {
+		return false
+	}
+	model.EntryID = match.EntryID
+	model.Topic = match.Topic
+	model.FileExtension = match.FileExtension
+	for i, check := range m.checks {
+		if check == nil {
+			continue
+		}
+		var rendered strings.Builder
+		if err := check.Execute(&rendered, model); err != nil || rendered.String() != groups[i+1] {
+			return false
+		}
+	}
+	return true
+}
+
+// candidateDates returns the dates (in ascending order) that could have produced the captured values
+// Each candidate must be verified by rendering the pattern, as not every captured value is used to derive them
+func candidateDates(values map[string]string, loc *time.Location) []time.Time {
+	year, hasYear := recoverYear(values, "")
+	if !hasYear {
+		if weekCommencing, ok := recoverDate(values, "WkCom.", loc); ok {
+			return daysFrom(weekCommencing, 7)
+		}
+		return nil
+	}
+	if date, ok := recoverDate(values, "", loc); ok {
+		return []time.Time{date}
+	}
+	if yearDay, ok := atoi(values["Year.Day.Num"], values["Year.Day.Pad"], trimOrdinal(values["Year.Day.Ord"])); ok {
+		return []time.Time{time.Date(year, time.January, yearDay, 0, 0, 0, 0, loc)}
+	}
+	if weekCommencing, ok := recoverDate(values, "WkCom.", loc); ok {
+		return daysFrom(weekCommencing, 7)
+	}
+	if isoWeek, ok := atoi(values["Year.Week.Num"], values["Year.Week.Pad"], trimOrdinal(values["Year.Week.Ord"])); ok {
+		// The Monday of ISO week 1 is the week commencing date of the 4th of January
+		firstMonday := caltools.WeekCommencing(time.Date(year, time.January, 4, 0, 0, 0, 0, loc))
+		return daysFrom(firstMonday.AddDate(0, 0, (isoWeek-1)*7), 7)
+	}
+	if month, ok := recoverMonth(values, ""); ok {
+		firstOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+		return daysFrom(firstOfMonth, caltools.DaysInMonth(firstOfMonth))
+	}
+	firstOfYear := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	return daysFrom(firstOfYear, caltools.DaysInYear(firstOfYear))
+}
+
+// recoverDate recovers a full date from the captured values of fields with the given prefix (e.g. "WkCom.")
+func recoverDate(values map[string]string, prefix string, loc *time.Location) (time.Time, bool) {
+	year, hasYear := recoverYear(values, prefix)
+	month, hasMonth := recoverMonth(values, prefix)
+	day, hasDay := atoi(values[prefix+"Day.Num"], values[prefix+"Day.Pad"], trimOrdinal(values[prefix+"Day.Ord"]))
+	if !hasYear || !hasMonth || !hasDay || month < 1 || month > 12 || day < 1 {
+		return time.Time{}, false
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+	if date.Day() != day {
+		// e.g. 31st June
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// recoverYear recovers the year from the captured values of fields with the given prefix
+// Short years are assumed to be in the 21st century
+func recoverYear(values map[string]string, prefix string) (int, bool) {
+	if year, ok := atoi(values[prefix+"Year.Num"]); ok {
+		return year, true
+	}
+	if year, ok := atoi(values[prefix+"Year.Short"]); ok {
+		return 2000 + year, true
+	}
+	return 0, false
+}
+
+// recoverMonth recovers the month number from the captured values of fields with the given prefix
+func recoverMonth(values map[string]string, prefix string) (int, bool) {
+	if month, ok := atoi(values[prefix+"Month.Num"], values[prefix+"Month.Pad"], trimOrdinal(values[prefix+"Month.Ord"])); ok {
+		return month, true
+	}
+	for _, field := range []string{"Month.Name", "Month.Short"} {
+		name := values[prefix+field]
+		if name == "" {
+			continue
+		}
+		for month := time.January; month <= time.December; month++ {
+			if name == month.String() || name == month.String()[:3] {
+				return int(month), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// daysFrom returns count consecutive days, starting from the given date
+func daysFrom(start time.Time, count int) []time.Time {
+	days := make([]time.Time, 0, count)
+	for i := 0; i < count; i++ {
+		days = append(days, start.AddDate(0, 0, i))
+	}
+	return days
+}
+
+// trimOrdinal removes the ordinal suffix from a number (e.g. "2nd" => "2")
+func trimOrdinal(value string) string {
+	return strings.TrimRight(value, "stndrh")
+}
+
+// atoi converts the first non-empty value to an integer, reporting whether the conversion succeeded
+func atoi(values ...string) (int, bool) {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		return number, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,276 @@
+package templating
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcherMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		literals map[string]string
+		path     string
+		want     *PatternMatch
+		wantOK   bool
+	}{
+		{
+			name:    "numeric date",
+			pattern: "{{.Year.Num}}/{{.Month.Pad}}/{{.Day.Pad}}/{{.EntryID}}.{{.FileExtension}}",
+			path:    "2024/08/02/note.md",
+			want: &PatternMatch{
+				Date:          time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				EntryID:       "note",
+				FileExtension: "md",
+			},
+			wantOK: true,
+		},
+		{
+			name:     "names and ordinals",
+			pattern:  "{{.EntryID}}s/{{.Day.Short}}-{{.Day.Ord}}-{{.Month.Short}}-{{.Year.Num}}.{{.FileExtension}}",
+			literals: map[string]string{"EntryID": "standup"},
+			path:     "standups/Fri-2nd-Aug-2024.md",
+			want: &PatternMatch{
+				Date:          time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				EntryID:       "standup",
+				FileExtension: "md",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "weekday disagrees with date",
+			pattern: "{{.Day.Short}}-{{.Day.Ord}}-{{.Month.Short}}-{{.Year.Num}}.md",
+			path:    "Thu-2nd-Aug-2024.md",
+			wantOK:  false,
+		},
+		{
+			name:    "ordinal disagrees with day",
+			pattern: "{{.Day.Ord}}-{{.Month.Short}}-{{.Year.Num}}.md",
+			path:    "2th-Aug-2024.md",
+			wantOK:  false,
+		},
+		{
+			name:    "week commencing directory and weekday",
+			pattern: "wc-{{.WkCom.Day.Pad}}-{{.WkCom.Month.Pad}}-{{.WkCom.Year.Short}}/{{.Day.Name}}.md",
+			path:    "wc-29-07-24/Friday.md",
+			want: &PatternMatch{
+				Date: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			},
+			wantOK: true,
+		},
+		{
+			name:    "week commencing only uses the Monday",
+			pattern: "weekly/{{.WkCom.Year.Num}}-{{.WkCom.Month.Pad}}-{{.WkCom.Day.Pad}}.md",
+			path:    "weekly/2024-07-29.md",
+			want: &PatternMatch{
+				Date: time.Date(2024, time.July, 29, 0, 0, 0, 0, time.UTC),
+			},
+			wantOK: true,
+		},
+		{
+			name:    "week commencing is not a Monday",
+			pattern: "weekly/{{.WkCom.Year.Num}}-{{.WkCom.Month.Pad}}-{{.WkCom.Day.Pad}}.md",
+			path:    "weekly/2024-07-30.md",
+			wantOK:  false,
+		},
+		{
+			name:    "day of the year",
+			pattern: "{{.EntryID}}s/{{.Year.Day.Num}}-of-{{.Year.DaysIn}}-{{.Year.Num}}",
+			path:    "notes/215-of-366-2024",
+			want: &PatternMatch{
+				Date:    time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				EntryID: "note",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "iso week and weekday",
+			pattern: "{{.Year.Num}}/week-{{.Year.Week.Pad}}/{{.Year.Week.Day.Num}}.md",
+			path:    "2024/week-31/5.md",
+			want: &PatternMatch{
+				Date: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			},
+			wantOK: true,
+		},
+		{
+			name:    "month only uses the 1st",
+			pattern: "{{.Year.Num}}/{{.Month.Name}}/review.md",
+			path:    "2024/August/review.md",
+			want: &PatternMatch{
+				Date: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantOK: true,
+		},
+		{
+			name:    "invalid month name",
+			pattern: "{{.Year.Num}}/{{.Month.Name}}/review.md",
+			path:    "2024/Augtober/review.md",
+			wantOK:  false,
+		},
+		{
+			name:    "topic with slashes",
+			pattern: "{{.Topic}}/{{.Year.Short}}-{{.Month.Num}}-{{.Day.Num}}.md",
+			path:    "project/alpha/24-8-2.md",
+			want: &PatternMatch{
+				Date:  time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				Topic: "project/alpha",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "escaped topic",
+			pattern: "{{.Topic}}/{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+			path:    "planning &amp; more/2024-08-02.md",
+			want: &PatternMatch{
+				Date:  time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				Topic: "planning & more",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "no date in pattern",
+			pattern: "{{.Topic}}.md",
+			path:    "retro.md",
+			want:    &PatternMatch{Topic: "retro"},
+			wantOK:  true,
+		},
+		{
+			name:     "literal mismatch",
+			pattern:  "{{.EntryID}}s/{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+			literals: map[string]string{"EntryID": "standup"},
+			path:     "notes/2024-08-02.md",
+			wantOK:   false,
+		},
+		{
+			name:    "invalid date",
+			pattern: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+			path:    "2024-06-31.md",
+			wantOK:  false,
+		},
+		{
+			name:    "slugified topic",
+			pattern: "{{.Topic | slugify}}/{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+			path:    "project-a-q3/2024-08-02.md",
+			want: &PatternMatch{
+				Date:  time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				Topic: "project-a-q3",
+			},
+			wantOK: true,
+		},
+		{
+			name:     "literal pipeline",
+			pattern:  "{{.EntryID | upper}}/{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+			literals: map[string]string{"EntryID": "standup"},
+			path:     "STANDUP/2024-08-02.md",
+			want: &PatternMatch{
+				Date:    time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				EntryID: "standup",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "date pipeline verified",
+			pattern: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}-until-{{.Time | addWeeks 1 | format \"Jan 2\"}}.md",
+			path:    "2024-08-02-until-Aug 9.md",
+			want: &PatternMatch{
+				Date: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			},
+			wantOK: true,
+		},
+		{
+			name:    "date pipeline disagrees",
+			pattern: "{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}-until-{{.Time | addWeeks 1 | format \"Jan 2\"}}.md",
+			path:    "2024-08-02-until-Aug 8.md",
+			wantOK:  false,
+		},
+		{
+			name:    "formatted date",
+			pattern: "{{.Time | format \"2006-01-02\"}}.md",
+			path:    "2024-08-02.md",
+			want: &PatternMatch{
+				Date: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+			},
+			wantOK: true,
+		},
+		{
+			name:    "formatted date with offset",
+			pattern: "{{.EntryID}}/{{.Time | addWeeks 1 | addDays -1 | format \"Mon 2 Jan 2006\"}}.md",
+			path:    "review/Thu 8 Aug 2024.md",
+			want: &PatternMatch{
+				Date:    time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC),
+				EntryID: "review",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "formatted date is not a date",
+			pattern: "{{.Time | format \"2006-01-02\"}}.md",
+			path:    "notes.md",
+			wantOK:  false,
+		},
+		{
+			name:    "formatted weekday disagrees with date",
+			pattern: "{{.Time | format \"Mon 2006-01-02\"}}.md",
+			path:    "Thu 2024-08-02.md",
+			wantOK:  false,
+		},
+		{
+			name:    "repeated field disagrees",
+			pattern: "{{.Year.Num}}/{{.Year.Num}}-{{.Month.Pad}}-{{.Day.Pad}}.md",
+			path:    "2024/2023-06-01.md",
+			wantOK:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := NewMatcher(tt.pattern, tt.literals)
+			assert.NoError(t, err)
+			got, ok := matcher.Match(tt.path, time.UTC)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewMatcherErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+	}{
+		{name: "unknown field", pattern: "{{.Year.Decade}}.md"},
+		{name: "undefined function", pattern: "{{.Topic | shout}}.md"},
+		{name: "conditional", pattern: "{{if .Topic}}{{.Topic}}{{end}}.md"},
+		{name: "date only from a function", pattern: "{{.Time | format \"Jan 2\"}}.md"},
+		{name: "date only from a variable", pattern: "{{$t := .Time}}{{$t | format \"2006-01-02\"}}.md"},
+		{name: "invalid syntax", pattern: "{{.Year.Num"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMatcher(tt.pattern, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReverseParsePattern(t *testing.T) {
+	pattern := "{{.Day.Short}}-{{.Day.Ord}}-{{.Month.Short}}-{{.Year.Num}}.{{.FileExtension}}"
+
+	match, err := ReverseParsePattern(pattern, "Fri-2nd-Aug-2024.md", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC), match.Date)
+	assert.Equal(t, "md", match.FileExtension)
+
+	// Round trip: the recovered values render back to the same path
+	templateData, _ := PrepareTemplateData(match.Date)
+	templateData.FileExtension = match.FileExtension
+	path, err := templateData.ParsePattern(pattern)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fri-2nd-Aug-2024.md", path)
+
+	_, err = ReverseParsePattern(pattern, "notes/Fri-2nd-Aug-2024.md", time.UTC)
+	assert.ErrorIs(t, err, ErrNoMatch)
+}
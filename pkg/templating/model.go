@@ -1,5 +1,7 @@
 package templating
 
+import "time"
+
 type Day struct {
 	// Number of the day
 	Num string
@@ -114,6 +116,10 @@ type TemplateModel struct {
 	// WkCom (Week Commencing) date contains the date of the Monday of the week containing the current date
 	WkCom Date
 
+	// Time is the date and time the model was prepared for, for use with the date functions
+	// (e.g. {{.Time | addDays 1 | format "2006-01-02"}})
+	Time time.Time
+
 	// EntryID is the name of the entry type (e.g. notes/entry/diary/todo/meeting)
 	EntryID string
 
@@ -122,4 +128,11 @@ type TemplateModel struct {
 
 	// Topic is the name of the topic for the entry (e.g. "project A/B/C")
 	Topic string
+
+	// Tags are the tags of the entry (e.g. {{range .Tags}}#{{.}} {{end}})
+	Tags []string
+
+	// CarryOver is the unfinished tasks of the previous entry of the same type, as a Markdown task list
+	// (only set for document templates)
+	CarryOver string
 }
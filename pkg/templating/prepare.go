@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	texttemplate "text/template"
 	"time"
 
 	"github.com/matthewchivers/journal/pkg/caltools"
@@ -18,6 +19,7 @@ func PrepareTemplateData(time time.Time) (TemplateModel, error) {
 		Month: PopulateMonth(time),
 		Day:   PopulateDay(time),
 		WkCom: PopulateDate(weekCommencing),
+		Time:  time,
 	}
 	return data, nil
 }
@@ -35,7 +37,7 @@ func PopulateDate(time time.Time) Date {
 
 // ParsePattern creates a new path for a journal entry based on a path template
 func (tm *TemplateModel) ParsePattern(pattern string) (string, error) {
-	t, err := template.New("path").Parse(pattern)
+	t, err := newPatternTemplate(pattern)
 	if err != nil {
 		return "", err
 	}
@@ -51,6 +53,32 @@ func (tm *TemplateModel) ParsePattern(pattern string) (string, error) {
 	return parsedTemplate, nil
 }
 
+// newPatternTemplate parses a (path) pattern into a template
+func newPatternTemplate(pattern string) (*template.Template, error) {
+	return template.New("path").Funcs(FuncMap()).Parse(pattern)
+}
+
+// ParseDocument renders a document template (e.g. the initial content of a new entry)
+// Documents are rendered as plain text, so unlike patterns no HTML escaping is applied
+func (tm *TemplateModel) ParseDocument(name string, document string) (string, error) {
+	return RenderDocument(name, document, tm)
+}
+
+// RenderDocument renders a document template with any data (e.g. a model embedding TemplateModel)
+func RenderDocument(name string, document string, data interface{}) (string, error) {
+	t, err := texttemplate.New(name).Funcs(FuncMap()).Parse(document)
+	if err != nil {
+		return "", err
+	}
+
+	var documentB bytes.Buffer
+	if err := t.Execute(&documentB, data); err != nil {
+		return "", err
+	}
+
+	return documentB.String(), nil
+}
+
 // PopulateDay returns a WeekDay struct using the day of the month
 func PopulateDay(time time.Time) WeekDay {
 	wd := &WeekDay{
@@ -123,3 +123,50 @@ func TestPopulateMonth(t *testing.T) {
 	assert.Equal(t, "Jun", month.Short)
 	assert.Equal(t, "30", month.DaysIn)
 }
+
+func TestParseDocument(t *testing.T) {
+	testTime := time.Date(2024, 6, 28, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		document string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "plain document",
+			document: "# Notes\n",
+			expected: "# Notes\n",
+		},
+		{
+			name:     "date fields",
+			document: "# {{.EntryID}} - {{.Day.Name}} {{.Day.Ord}} {{.Month.Name}} {{.Year.Num}}\n",
+			expected: "# standup - Friday 28th June 2024\n",
+		},
+		{
+			name:     "no html escaping",
+			document: "## {{.Topic}}\n",
+			expected: "## R&D <review>\n",
+		},
+		{
+			name:     "invalid template",
+			document: "{{.Day.Name",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateData, _ := PrepareTemplateData(testTime)
+			templateData.EntryID = "standup"
+			templateData.Topic = "R&D <review>"
+			document, err := templateData.ParseDocument("test", tt.document)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, document)
+		})
+	}
+}
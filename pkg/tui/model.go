@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/matthewchivers/journal/pkg/caltools"
+	"github.com/matthewchivers/journal/pkg/catalog"
+)
+
+// dayKey is the layout of the keys of days with entries
+const dayKey = time.DateOnly
+
+// daysInWeek is the number of columns in the calendar
+const daysInWeek = 7
+
+// Focus is the pane that receives the movement keys
+type Focus int
+
+const (
+	// FocusCalendar moves the selected day
+	FocusCalendar Focus = iota
+
+	// FocusEntries moves the selected entry of the day
+	FocusEntries
+
+	// FocusPicker moves the selected entry type of a new entry
+	FocusPicker
+)
+
+// Model is the state of the terminal UI
+type Model struct {
+	// Selected is the selected day (at midnight)
+	Selected time.Time
+
+	// Today is the current day (at midnight)
+	Today time.Time
+
+	// Focus is the pane that receives the movement keys
+	Focus Focus
+
+	// Entry is the index of the selected entry of the selected day
+	Entry int
+
+	// Scroll is the number of lines the preview is scrolled by
+	Scroll int
+
+	// EntryIDs are the configured entry types (offered when creating a new entry)
+	EntryIDs []string
+
+	// EntryType is the index of the selected entry type in the new entry picker
+	EntryType int
+
+	// Status is the message shown in the status line
+	Status string
+
+	// byDay are the entries of each day, by date
+	byDay map[string][]catalog.Record
+}
+
+// NewModel creates a model with the given day selected (and treated as today)
+func NewModel(today time.Time, entryIDs []string, records []catalog.Record) *Model {
+	today = caltools.StartOfDay(today)
+	m := &Model{Selected: today, Today: today, EntryIDs: entryIDs}
+	m.SetRecords(records)
+	return m
+}
+
+// SetRecords replaces the entries shown (e.g. after creating an entry)
+// Entries without a date cannot be shown on the calendar, so are left out
+func (m *Model) SetRecords(records []catalog.Record) {
+	m.byDay = map[string][]catalog.Record{}
+	for _, record := range records {
+		if record.Date.IsZero() {
+			continue
+		}
+		key := record.Date.Format(dayKey)
+		m.byDay[key] = append(m.byDay[key], record)
+	}
+	m.clampEntry()
+}
+
+// Entries returns the entries of the selected day
+func (m *Model) Entries() []catalog.Record {
+	return m.EntriesOn(m.Selected)
+}
+
+// EntriesOn returns the entries of the given day
+func (m *Model) EntriesOn(day time.Time) []catalog.Record {
+	return m.byDay[day.Format(dayKey)]
+}
+
+// SelectedEntry returns the selected entry of the selected day
+func (m *Model) SelectedEntry() (catalog.Record, bool) {
+	entries := m.Entries()
+	if len(entries) == 0 {
+		return catalog.Record{}, false
+	}
+	return entries[m.Entry], true
+}
+
+// MoveDays moves the selected day by the given number of days
+func (m *Model) MoveDays(days int) {
+	m.selectDay(m.Selected.AddDate(0, 0, days))
+}
+
+// MoveMonths moves the selected day by the given number of months (to the last day of the month, if the month is
+// shorter)
+func (m *Model) MoveMonths(months int) {
+	first := time.Date(m.Selected.Year(), m.Selected.Month()+time.Month(months), 1, 0, 0, 0, 0, m.Selected.Location())
+	day := min(m.Selected.Day(), caltools.DaysInMonth(first))
+	m.selectDay(first.AddDate(0, 0, day-1))
+}
+
+// SelectToday selects the current day
+func (m *Model) SelectToday() {
+	m.selectDay(m.Today)
+}
+
+// MoveEntry moves the selected entry of the day by the given number of entries
+func (m *Model) MoveEntry(entries int) {
+	m.Entry += entries
+	m.clampEntry()
+	m.Scroll = 0
+}
+
+// ScrollPreview scrolls the preview by the given number of lines
+func (m *Model) ScrollPreview(lines int) {
+	m.Scroll = max(m.Scroll+lines, 0)
+}
+
+// MoveEntryType moves the selected entry type in the new entry picker
+func (m *Model) MoveEntryType(types int) {
+	m.EntryType = min(max(m.EntryType+types, 0), max(len(m.EntryIDs)-1, 0))
+}
+
+// Calendar returns the weeks (Monday to Sunday) of the selected day's month, with zero days padding the weeks
+// before the first and after the last day of the month
+func (m *Model) Calendar() [][]time.Time {
+	first := time.Date(m.Selected.Year(), m.Selected.Month(), 1, 0, 0, 0, 0, m.Selected.Location())
+	cells := make([]time.Time, (int(first.Weekday())+6)%daysInWeek)
+	for day := 0; day < caltools.DaysInMonth(first); day++ {
+		cells = append(cells, first.AddDate(0, 0, day))
+	}
+	for len(cells)%daysInWeek != 0 {
+		cells = append(cells, time.Time{})
+	}
+	weeks := [][]time.Time{}
+	for i := 0; i < len(cells); i += daysInWeek {
+		weeks = append(weeks, cells[i:i+daysInWeek])
+	}
+	return weeks
+}
+
+// selectDay selects a day, resetting the selected entry and preview
+func (m *Model) selectDay(day time.Time) {
+	m.Selected = caltools.StartOfDay(day)
+	m.Entry = 0
+	m.Scroll = 0
+}
+
+// clampEntry keeps the selected entry within the entries of the selected day
+func (m *Model) clampEntry() {
+	m.Entry = min(max(m.Entry, 0), max(len(m.Entries())-1, 0))
+}
@@ -0,0 +1,245 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/matthewchivers/journal/pkg/catalog"
+)
+
+// Actions are the operations the terminal UI performs on the journal
+type Actions interface {
+	// Records returns the entries of the journal, sorted by date
+	Records() ([]catalog.Record, error)
+
+	// Open opens the file of an entry in the editor
+	Open(record catalog.Record) error
+
+	// Create creates a new entry of the given type for the given day, returning the path of its file
+	Create(entryID string, day time.Time) (string, error)
+}
+
+// UI is a full-screen terminal interface for browsing and creating entries
+type UI struct {
+	// screen is the terminal screen
+	screen tcell.Screen
+
+	// model is the state of the interface
+	model *Model
+
+	// actions are the operations performed on the journal
+	actions Actions
+
+	// preview is the cached preview of an entry
+	preview preview
+}
+
+// specialKeys translates special keys into the characters with the same binding
+var specialKeys = map[tcell.Key]rune{
+	tcell.KeyLeft:  'h',
+	tcell.KeyDown:  'j',
+	tcell.KeyUp:    'k',
+	tcell.KeyRight: 'l',
+	tcell.KeyPgUp:  '[',
+	tcell.KeyPgDn:  ']',
+	tcell.KeyTab:   '\t',
+	tcell.KeyEnter: '\r',
+}
+
+// globalKeys are the key bindings of both the calendar and the entry list
+var globalKeys = map[rune]func(ui *UI){
+	'\t': (*UI).toggleFocus,
+	'\r': (*UI).openSelected,
+	'o':  (*UI).openSelected,
+	'n':  func(ui *UI) { ui.model.Focus = FocusPicker },
+	'r':  (*UI).reload,
+}
+
+// calendarKeys are the key bindings of the calendar pane
+var calendarKeys = map[rune]func(m *Model){
+	'h': func(m *Model) { m.MoveDays(-1) },
+	'l': func(m *Model) { m.MoveDays(1) },
+	'k': func(m *Model) { m.MoveDays(-daysInWeek) },
+	'j': func(m *Model) { m.MoveDays(daysInWeek) },
+	'[': func(m *Model) { m.MoveMonths(-1) },
+	']': func(m *Model) { m.MoveMonths(1) },
+	't': func(m *Model) { m.SelectToday() },
+}
+
+// entryKeys are the key bindings of the entry list
+var entryKeys = map[rune]func(m *Model){
+	'k': func(m *Model) { m.MoveEntry(-1) },
+	'j': func(m *Model) { m.MoveEntry(1) },
+	'h': func(m *Model) { m.Focus = FocusCalendar },
+}
+
+// pickerKeys are the key bindings of the new entry picker
+var pickerKeys = map[rune]func(m *Model){
+	'k': func(m *Model) { m.MoveEntryType(-1) },
+	'j': func(m *Model) { m.MoveEntryType(1) },
+}
+
+// previewKeys are the key bindings that scroll the preview (in any pane)
+var previewKeys = map[rune]func(m *Model){
+	'J': func(m *Model) { m.ScrollPreview(1) },
+	'K': func(m *Model) { m.ScrollPreview(-1) },
+}
+
+// New creates a terminal UI on the screen (which is initialised by Run)
+func New(screen tcell.Screen, model *Model, actions Actions) *UI {
+	return &UI{screen: screen, model: model, actions: actions}
+}
+
+// Run runs the interface until the user quits
+func (ui *UI) Run() error {
+	if err := ui.screen.Init(); err != nil {
+		return err
+	}
+	defer ui.screen.Fini()
+	for {
+		ui.draw()
+		switch ev := ui.screen.PollEvent().(type) {
+		case *tcell.EventResize:
+			ui.screen.Sync()
+		case *tcell.EventKey:
+			if ui.handleKey(ev) {
+				return nil
+			}
+		case nil:
+			// The screen has been finalised
+			return nil
+		}
+	}
+}
+
+// handleKey handles a key press, returning true if the user has quit
+func (ui *UI) handleKey(ev *tcell.EventKey) bool {
+	m := ui.model
+	m.Status = ""
+	if m.Focus == FocusPicker {
+		ui.handlePickerKey(ev)
+		return false
+	}
+	if isQuit(ev) {
+		return true
+	}
+	if action, ok := globalKeys[keyRune(ev)]; ok {
+		action(ui)
+		return false
+	}
+	bindings := calendarKeys
+	if m.Focus == FocusEntries {
+		bindings = entryKeys
+	}
+	ui.bind(ev, bindings)
+	return false
+}
+
+// handlePickerKey handles a key press in the new entry picker
+func (ui *UI) handlePickerKey(ev *tcell.EventKey) {
+	switch {
+	case isQuit(ev):
+		ui.model.Focus = FocusCalendar
+	case ev.Key() == tcell.KeyEnter:
+		ui.model.Focus = FocusCalendar
+		ui.createEntry()
+	default:
+		ui.bind(ev, pickerKeys)
+	}
+}
+
+// bind calls the binding of a key (if any), looking in the preview bindings if the pane has no binding for it
+func (ui *UI) bind(ev *tcell.EventKey, bindings map[rune]func(m *Model)) {
+	key := keyRune(ev)
+	if binding, ok := bindings[key]; ok {
+		binding(ui.model)
+	} else if binding, ok := previewKeys[key]; ok {
+		binding(ui.model)
+	}
+}
+
+// keyRune returns the character bound to a key press
+func keyRune(ev *tcell.EventKey) rune {
+	if key, ok := specialKeys[ev.Key()]; ok {
+		return key
+	}
+	return ev.Rune()
+}
+
+// isQuit reports whether a key press quits (or, in the picker, cancels)
+func isQuit(ev *tcell.EventKey) bool {
+	return ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC || ev.Rune() == 'q'
+}
+
+// toggleFocus moves the focus between the calendar and the entry list
+func (ui *UI) toggleFocus() {
+	if ui.model.Focus == FocusCalendar && len(ui.model.Entries()) > 0 {
+		ui.model.Focus = FocusEntries
+		return
+	}
+	ui.model.Focus = FocusCalendar
+}
+
+// openSelected opens the selected entry in the editor
+func (ui *UI) openSelected() {
+	record, ok := ui.model.SelectedEntry()
+	if !ok {
+		ui.model.Status = "no entries on this day - press n to create one"
+		return
+	}
+	ui.open(record)
+}
+
+// open opens an entry in the editor, suspending the interface while the editor runs in the terminal
+func (ui *UI) open(record catalog.Record) {
+	if err := ui.screen.Suspend(); err != nil {
+		ui.model.Status = err.Error()
+		return
+	}
+	err := ui.actions.Open(record)
+	if resumeErr := ui.screen.Resume(); resumeErr != nil && err == nil {
+		err = resumeErr
+	}
+	ui.preview = preview{}
+	if err != nil {
+		ui.model.Status = "error opening entry: " + err.Error()
+	}
+}
+
+// createEntry creates an entry of the type selected in the picker for the selected day, then opens it
+func (ui *UI) createEntry() {
+	m := ui.model
+	if len(m.EntryIDs) == 0 {
+		m.Status = "no entry types configured"
+		return
+	}
+	entryID := m.EntryIDs[m.EntryType]
+	filePath, err := ui.actions.Create(entryID, m.Selected)
+	if err != nil {
+		m.Status = "error creating entry: " + err.Error()
+		return
+	}
+	ui.reload()
+	for i, record := range m.Entries() {
+		if record.Path == filePath {
+			m.Entry = i
+			ui.open(record)
+			return
+		}
+	}
+	// The file is not recognised by the entry's patterns (or is dated differently), so it is not listed
+	ui.open(catalog.Record{Path: filePath, EntryID: entryID})
+	m.Status = fmt.Sprintf("created %s", filePath)
+}
+
+// reload reloads the entries of the journal
+func (ui *UI) reload() {
+	records, err := ui.actions.Records()
+	if err != nil {
+		ui.model.Status = "error loading entries: " + err.Error()
+		return
+	}
+	ui.model.SetRecords(records)
+	ui.preview = preview{}
+}
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeActions records the operations performed by the interface
+type fakeActions struct {
+	records []catalog.Record
+	opened  []string
+	created []string
+	dir     string
+}
+
+func (f *fakeActions) Records() ([]catalog.Record, error) {
+	return f.records, nil
+}
+
+func (f *fakeActions) Open(record catalog.Record) error {
+	f.opened = append(f.opened, record.Path)
+	return nil
+}
+
+func (f *fakeActions) Create(entryID string, day time.Time) (string, error) {
+	filePath := filepath.Join(f.dir, entryID+"-"+day.Format(time.DateOnly)+".md")
+	if err := os.WriteFile(filePath, []byte("# New\n"), 0600); err != nil {
+		return "", err
+	}
+	f.created = append(f.created, filePath)
+	f.records = append(f.records, catalog.Record{Path: filePath, EntryID: entryID, Date: day})
+	return filePath, nil
+}
+
+func day(d int) time.Time {
+	return time.Date(2024, time.August, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestModel(t *testing.T) {
+	records := []catalog.Record{
+		{Path: "a.md", EntryID: "standup", Date: day(12)},
+		{Path: "b.md", EntryID: "meeting", Date: day(12)},
+		{Path: "c.md", EntryID: "note"},
+	}
+	m := NewModel(time.Date(2024, time.August, 12, 9, 30, 0, 0, time.UTC), []string{"meeting", "standup"}, records)
+	assert.Equal(t, day(12), m.Selected)
+	assert.Len(t, m.Entries(), 2)
+
+	m.MoveEntry(5)
+	assert.Equal(t, 1, m.Entry)
+	entry, ok := m.SelectedEntry()
+	assert.True(t, ok)
+	assert.Equal(t, "b.md", entry.Path)
+
+	m.MoveDays(1)
+	assert.Equal(t, day(13), m.Selected)
+	assert.Equal(t, 0, m.Entry)
+	_, ok = m.SelectedEntry()
+	assert.False(t, ok)
+
+	m.selectDay(time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC))
+	m.MoveMonths(1)
+	assert.Equal(t, time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), m.Selected)
+	m.SelectToday()
+	assert.Equal(t, day(12), m.Selected)
+
+	// August 2024 starts on a Thursday
+	weeks := m.Calendar()
+	assert.Len(t, weeks, 5)
+	assert.True(t, weeks[0][2].IsZero())
+	assert.Equal(t, day(1), weeks[0][3])
+	assert.Equal(t, day(31), weeks[4][5])
+
+	m.MoveEntryType(-1)
+	assert.Equal(t, 0, m.EntryType)
+	m.MoveEntryType(3)
+	assert.Equal(t, 1, m.EntryType)
+}
+
+func TestUI(t *testing.T) {
+	dir := t.TempDir()
+	standup := filepath.Join(dir, "standup.md")
+	assert.NoError(t, os.WriteFile(standup, []byte("# Stand-up\n- [ ] write report\n"), 0600))
+	actions := &fakeActions{dir: dir, records: []catalog.Record{{Path: standup, EntryID: "standup", Date: day(12)}}}
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	assert.NoError(t, screen.Init())
+	screen.SetSize(80, 24)
+	model := NewModel(day(12), []string{"meeting", "standup"}, actions.records)
+	ui := New(screen, model, actions)
+
+	ui.draw()
+	text := screenText(screen)
+	assert.Contains(t, text, "August 2024")
+	assert.Contains(t, text, "Monday 12 August 2024")
+	assert.Contains(t, text, "standup  standup.md")
+	assert.Contains(t, text, "- [ ] write report")
+
+	press := func(key tcell.Key, r rune) bool {
+		quit := ui.handleKey(tcell.NewEventKey(key, r, tcell.ModNone))
+		ui.draw()
+		return quit
+	}
+
+	press(tcell.KeyEnter, 0)
+	assert.Equal(t, []string{standup}, actions.opened)
+
+	press(tcell.KeyRight, 0)
+	assert.Equal(t, day(13), model.Selected)
+	assert.Contains(t, screenText(screen), "no entries - press n to create one")
+
+	press(tcell.KeyRune, 'n')
+	assert.Equal(t, FocusPicker, model.Focus)
+	assert.Contains(t, screenText(screen), "New entry for Tuesday 13 August 2024")
+	press(tcell.KeyEnter, 0)
+	assert.Equal(t, FocusCalendar, model.Focus)
+	assert.Equal(t, []string{filepath.Join(dir, "meeting-2024-08-13.md")}, actions.created)
+	assert.Equal(t, actions.created[0], actions.opened[1])
+	assert.Contains(t, screenText(screen), "meeting  meeting-2024-08-13.md")
+
+	assert.False(t, press(tcell.KeyRune, 'x'))
+	assert.True(t, press(tcell.KeyRune, 'q'))
+}
+
+// screenText returns the text on the screen, one line per row
+func screenText(screen tcell.SimulationScreen) string {
+	cells, width, _ := screen.GetContents()
+	var sb strings.Builder
+	for i, cell := range cells {
+		if len(cell.Runes) > 0 {
+			sb.WriteRune(cell.Runes[0])
+		}
+		if (i+1)%width == 0 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
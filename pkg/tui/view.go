@@ -0,0 +1,212 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/matthewchivers/journal/pkg/catalog"
+	"github.com/matthewchivers/journal/pkg/encryption"
+)
+
+const (
+	// calendarWidth is the width of the calendar pane (seven three-character days)
+	calendarWidth = 21
+
+	// sidebarWidth is the width of the left-hand side of the screen (the calendar pane and its margins)
+	sidebarWidth = calendarWidth + 2
+
+	// maxListHeight is the most entries listed before the preview
+	maxListHeight = 8
+
+	// tabWidth is the number of spaces tabs are shown as in the preview
+	tabWidth = 4
+)
+
+// help describes the key bindings, one per line
+var help = []string{
+	"←↓↑→ hjkl  move",
+	"[ ]        month",
+	"t          today",
+	"tab        entries",
+	"enter      open",
+	"n          new entry",
+	"J K        scroll",
+	"r          reload",
+	"q          quit",
+}
+
+var (
+	styleDefault  = tcell.StyleDefault
+	styleHeading  = styleDefault.Bold(true)
+	styleDim      = styleDefault.Dim(true)
+	styleSelected = styleDefault.Reverse(true)
+	styleHasEntry = styleDefault.Bold(true).Foreground(tcell.ColorGreen)
+)
+
+// preview is the content of an entry's file, cached between draws
+type preview struct {
+	path  string
+	lines []string
+}
+
+// draw draws the whole interface
+func (ui *UI) draw() {
+	ui.screen.Clear()
+	width, height := ui.screen.Size()
+	ui.drawCalendar()
+	for y := 0; y < height-1; y++ {
+		ui.screen.SetContent(sidebarWidth, y, tcell.RuneVLine, nil, styleDim)
+	}
+	x := sidebarWidth + 2
+	if ui.model.Focus == FocusPicker {
+		ui.drawPicker(x, width-x)
+	} else {
+		ui.drawEntries(x, width-x, height-1)
+	}
+	status := ui.model.Status
+	if status == "" {
+		status = "press q to quit, n to create an entry, enter to open the selected entry"
+	}
+	drawText(ui.screen, 0, height-1, width, styleSelected, " "+padRight(status, width-1))
+	ui.screen.Show()
+}
+
+// drawCalendar draws the calendar of the selected month, followed by the key bindings
+func (ui *UI) drawCalendar() {
+	m := ui.model
+	title := m.Selected.Format("January 2006")
+	drawText(ui.screen, 1+(calendarWidth-len(title))/2, 0, calendarWidth, styleHeading, title)
+	drawText(ui.screen, 1, 1, calendarWidth, styleDim, "Mo Tu We Th Fr Sa Su")
+	for row, week := range m.Calendar() {
+		for col, day := range week {
+			if day.IsZero() {
+				continue
+			}
+			drawText(ui.screen, 1+col*3, 2+row, 2, ui.dayStyle(day), fmt.Sprintf("%2d", day.Day()))
+		}
+	}
+	for i, line := range help {
+		drawText(ui.screen, 1, 10+i, calendarWidth, styleDim, line)
+	}
+}
+
+// dayStyle returns the style of a day in the calendar: marked if it has entries, underlined if it is today, and
+// highlighted if it is selected
+func (ui *UI) dayStyle(day time.Time) tcell.Style {
+	m := ui.model
+	style := styleDefault
+	if len(m.EntriesOn(day)) > 0 {
+		style = styleHasEntry
+	}
+	if day.Equal(m.Today) {
+		style = style.Underline(true)
+	}
+	if day.Equal(m.Selected) {
+		style = style.Reverse(true)
+	}
+	return style
+}
+
+// drawEntries draws the entries of the selected day, followed by a preview of the selected entry
+func (ui *UI) drawEntries(x, width, height int) {
+	m := ui.model
+	entries := m.Entries()
+	drawText(ui.screen, x, 0, width, styleHeading, m.Selected.Format("Monday 2 January 2006"))
+	if len(entries) == 0 {
+		drawText(ui.screen, x, 2, width, styleDim, "no entries - press n to create one")
+		return
+	}
+
+	// Scroll the list to keep the selected entry in view
+	first := max(m.Entry-maxListHeight+1, 0)
+	for i, record := range entries[first:min(first+maxListHeight, len(entries))] {
+		style := styleDefault
+		if first+i == m.Entry {
+			style = styleHeading
+			if m.Focus == FocusEntries {
+				style = styleSelected
+			}
+		}
+		drawText(ui.screen, x, 2+i, width, style, padRight(entryLabel(record), width))
+	}
+
+	y := 3 + min(len(entries), maxListHeight)
+	for col := x; col < x+width; col++ {
+		ui.screen.SetContent(col, y, tcell.RuneHLine, nil, styleDim)
+	}
+	record, _ := m.SelectedEntry()
+	lines := ui.previewLines(record.Path)
+	m.Scroll = min(m.Scroll, max(len(lines)-1, 0))
+	for i, line := range lines[m.Scroll:] {
+		if y+1+i >= height {
+			break
+		}
+		drawText(ui.screen, x, y+1+i, width, styleDefault, line)
+	}
+}
+
+// drawPicker draws the entry types that can be created for the selected day
+func (ui *UI) drawPicker(x, width int) {
+	m := ui.model
+	drawText(ui.screen, x, 0, width, styleHeading, "New entry for "+m.Selected.Format("Monday 2 January 2006"))
+	drawText(ui.screen, x, 1, width, styleDim, "↓↑ select, enter create, esc cancel")
+	for i, entryID := range m.EntryIDs {
+		style := styleDefault
+		if i == m.EntryType {
+			style = styleSelected
+		}
+		drawText(ui.screen, x, 3+i, width, style, padRight(" "+entryID, width))
+	}
+}
+
+// previewLines returns the lines of an entry's file (cached until the entry changes, or is opened or reloaded)
+func (ui *UI) previewLines(filePath string) []string {
+	if ui.preview.path == filePath && ui.preview.lines != nil {
+		return ui.preview.lines
+	}
+	if encryption.IsEncrypted(filePath) {
+		return []string{"encrypted - press enter to open"}
+	}
+	// #nosec G304: Potential file inclusion via variable
+	// The path was found by walking the configured base directories
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return []string{"unable to read " + filePath + ": " + err.Error()}
+	}
+	text := strings.ReplaceAll(string(content), "\t", strings.Repeat(" ", tabWidth))
+	ui.preview = preview{path: filePath, lines: strings.Split(strings.TrimRight(text, "\n"), "\n")}
+	return ui.preview.lines
+}
+
+// entryLabel returns the label of an entry in the list: its entry ID, topic and file name
+func entryLabel(record catalog.Record) string {
+	label := record.EntryID
+	if record.Topic != "" {
+		label += " (" + record.Topic + ")"
+	}
+	return label + "  " + filepath.Base(record.Path)
+}
+
+// drawText draws text on one line, truncated to the given width
+func drawText(screen tcell.Screen, x, y, width int, style tcell.Style, text string) {
+	col := 0
+	for _, r := range text {
+		if col >= width {
+			return
+		}
+		screen.SetContent(x+col, y, r, nil, style)
+		col++
+	}
+}
+
+// padRight pads text with spaces to the given width (so that highlighted lines fill the pane)
+func padRight(text string, width int) string {
+	if pad := width - len([]rune(text)); pad > 0 {
+		return text + strings.Repeat(" ", pad)
+	}
+	return text
+}